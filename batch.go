@@ -0,0 +1,108 @@
+package gqb
+
+import (
+	"context"
+	"fmt"
+
+	"database/sql"
+)
+
+// batchQuery is query and bind parameters which is executed in batch
+type batchQuery struct {
+	query string
+	binds []interface{}
+}
+
+// batchResult is sql.Result implementation which aggregates results of batch queries.
+// LastInsertId() returns the value of the first query, and RowsAffected() returns sum of all queries.
+type batchResult struct {
+	lastInsertId int64
+	rowsAffected int64
+}
+
+// sql.Result interface implementation
+func (b batchResult) LastInsertId() (int64, error) {
+	return b.lastInsertId, nil
+}
+
+// sql.Result interface implementation
+func (b batchResult) RowsAffected() (int64, error) {
+	return b.rowsAffected, nil
+}
+
+// Run multiple statement operations like chunked bulk insert in a single transaction.
+// If executor is already in transaction like *sql.Tx, operations run in it.
+func (q *QueryBuilder) Atomic() *QueryBuilder {
+	q = q.derive()
+	q.atomic = true
+	return q
+}
+
+// Execute batch queries and aggregate results.
+// Single query is executed directly and returns its result as it is.
+// Affected rows are checked with MaxAffected(), and batch runs in transaction to roll back when exceeded.
+func (q *QueryBuilder) execBatch(ctx context.Context, queries []batchQuery) (sql.Result, error) {
+	if len(queries) == 1 {
+		return q.execMutation(ctx, queries[0].query, queries[0].binds)
+	}
+	b, ok := q.db.(txBeginner)
+	inTx := ok && (q.atomic || q.maxAffected > 0)
+	if inTx && !q.compat.Capabilities().Transaction {
+		if q.atomic {
+			return nil, fmt.Errorf("Atomic() is not supported because driver doesn't have transaction")
+		}
+		inTx = false
+	}
+	if !inTx {
+		result, err := execQueries(ctx, q.db, queries)
+		if err != nil {
+			return nil, err
+		}
+		if q.maxAffected > 0 {
+			if err := q.checkAffected(result); err != nil {
+				return nil, err
+			}
+		}
+		return result, nil
+	}
+
+	tx, err := b.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	result, err := execQueries(ctx, tx, queries)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if q.maxAffected > 0 {
+		if err := q.checkAffected(result); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Execute queries in order and aggregate results
+func execQueries(ctx context.Context, db Executor, queries []batchQuery) (sql.Result, error) {
+	aggregated := batchResult{}
+	for i, bq := range queries {
+		result, err := db.ExecContext(ctx, bq.query, bq.binds...)
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			if id, err := result.LastInsertId(); err == nil {
+				aggregated.lastInsertId = id
+			}
+		}
+		if affected, err := result.RowsAffected(); err == nil {
+			aggregated.rowsAffected += affected
+		}
+	}
+	return aggregated, nil
+}
@@ -19,41 +19,57 @@ const (
 
 // bind() adds some value to bind slice values.
 // if value is time.Time struct, stringify with datetime
-func bind(b []interface{}, v interface{}) []interface{} {
+func bind(compat Compat, b []interface{}, v interface{}) []interface{} {
 	if t, ok := v.(time.Time); ok {
-		b = append(b, t.Format(datetimeFormat))
+		b = append(b, compat.FormatTime(t, datetimeFormat))
 	} else if t, ok := v.(Datetime); ok {
-		b = append(b, t.Format(datetimeFormat))
+		b = append(b, compat.FormatTime(t, datetimeFormat))
 	} else if t, ok := v.(Date); ok {
-		b = append(b, t.Format(dateFormat))
+		b = append(b, compat.FormatTime(t, dateFormat))
 	} else if t, ok := v.(Time); ok {
-		b = append(b, t.Format(timeFormat))
+		b = append(b, compat.FormatTime(t, timeFormat))
 	} else {
 		b = append(b, v)
 	}
 	return b
 }
 
+// bindValue() creates value phrase for INSERT/UPDATE and adds bind parameters.
+// Value is always bound to placeholder, except increment of version column which is made by optimistic lock.
+func bindValue(compat Compat, b []interface{}, v interface{}) (string, []interface{}) {
+	if c, ok := v.(increment); ok {
+		return quote(compat, string(c)) + " + 1", b
+	}
+	return compat.PlaceHolder(len(b) + 1), bind(compat, b, v)
+}
+
 // Create SELECT column name string.
 // If field is Raw type, field won't escape in order to unexpected quote string is added.
 //
-// Raw type -> Raw("COUNT(id)") -> COUNT(id)
-// Others   -> name             -> `name`
-func buildSelectFields(selects []interface{}) string {
+// Raw type           -> Raw("COUNT(id)")          -> COUNT(id)
+// Raw type with bind -> Raw("COALESCE(?)").Bind(1) -> COALESCE(?)
+// Others             -> name                      -> `name`
+func buildSelectFields(compat Compat, selects []interface{}, binds []interface{}) (string, []interface{}) {
 	if len(selects) == 0 {
-		return "*"
+		return "*", binds
 	}
 	fields := ""
 	for _, f := range selects {
 		if v, ok := f.(Raw); ok {
 			fields += v.String() + ", "
+		} else if v, ok := f.(RawExpr); ok {
+			var phrase string
+			phrase, binds = v.buildCompat(compat, binds)
+			fields += phrase + ", "
 		} else if v, ok := f.(alias); ok {
-			fields += v.String() + ", "
+			fields += v.build(compat) + ", "
 		} else if v, ok := f.(string); ok {
-			fields += quote(v) + ", "
+			fields += quote(compat, v) + ", "
+		} else if v, ok := f.(Ident); ok {
+			fields += quote(compat, v) + ", "
 		}
 	}
-	return strings.TrimRight(fields, ", ")
+	return strings.TrimRight(fields, ", "), binds
 }
 
 // Create WHERE clause string.
@@ -63,11 +79,23 @@ func buildSelectFields(selects []interface{}) string {
 // Raw type          -> Raw("COUNT(id)") -> COUNT(id)
 // column            -> name             -> `name`
 // column with table -> table.name       -> `table`.`name`
-func buildWhere(wheres []ConditionBuilder, binds []interface{}) (string, []interface{}) {
-	if len(wheres) == 0 {
+func buildWhere(compat Compat, wheres []ConditionBuilder, binds []interface{}) (string, []interface{}) {
+	where, binds := buildConditions(compat, wheres, binds)
+	if where == "" {
 		return "", binds
 	}
+	return " WHERE " + where, binds
+}
+
+// Check conditions produce any phrase, e.g. empty WhereGroup doesn't restrict rows
+func hasConditions(compat Compat, wheres []ConditionBuilder) bool {
+	where, _ := buildConditions(compat, wheres, []interface{}{})
+	return where != ""
+}
 
+// Create conditions string which concatenated with AND/OR.
+// Each condition is wrapped by parentheses, and condition which produces empty phrase like empty WhereGroup is skipped.
+func buildConditions(compat Compat, wheres []ConditionBuilder, binds []interface{}) (string, []interface{}) {
 	first := true
 	where := ""
 	c := ""
@@ -77,56 +105,111 @@ func buildWhere(wheres []ConditionBuilder, binds []interface{}) (string, []inter
 		if c != "" {
 			c = " " + c + " "
 		}
+		var clause string
+		clause, binds = buildCondition(compat, w, binds)
+		if clause == "" {
+			continue
+		}
 		if first {
 			c = ""
 			first = false
 		}
-		var clause string
-		clause, binds = w.Build(binds)
 		where += fmt.Sprintf("%s(%s)", c, clause)
 	}
-	return " WHERE " + where, binds
+	return where, binds
+}
+
+// compatBuilder is internal interface of ConditionBuilder which builds condition on the dialect of builder.
+// ConditionBuilder which doesn't implement it like user defined one is built by Build() on the dialect which is set by SetDriver().
+type compatBuilder interface {
+	buildCompat(compat Compat, binds []interface{}) (string, []interface{})
+}
+
+// Build condition on the dialect
+func buildCondition(compat Compat, c ConditionBuilder, binds []interface{}) (string, []interface{}) {
+	if b, ok := c.(compatBuilder); ok {
+		return b.buildCompat(compat, binds)
+	}
+	return c.Build(binds)
+}
+
+// Add condition to conditions with AND combination.
+// If conditions contain OR combination, they are wrapped by parentheses
+// in order to keep precedence like "(A OR B) AND (C)".
+func restrictConditions(wheres []ConditionBuilder, c ConditionBuilder) []ConditionBuilder {
+	for _, w := range wheres {
+		if w.Combine() == string(Or) {
+			return []ConditionBuilder{
+				conditionList{
+					conditions: wheres,
+					combine:    And,
+				},
+				c,
+			}
+		}
+	}
+	return append(append([]ConditionBuilder{}, wheres...), c)
 }
 
 // Create ORDER BY clause string.
-func buildOrderBy(orders []Order) string {
+func buildOrderBy(compat Compat, orders []Order) string {
 	if len(orders) == 0 {
 		return ""
 	}
 	order := []string{}
 	for _, o := range orders {
-		var s string
+		// Random order doesn't sort by field
 		if o.sort == Rand {
-			s = driverCompat.RandFunc()
+			order = append(order, compat.RandFunc())
 		} else {
-			s = string(o.sort)
+			order = append(order, quote(compat, o.field)+" "+string(o.sort))
 		}
-		order = append(order, quote(o.field)+" "+s)
 	}
 	return " ORDER BY " + strings.Join(order, ", ")
 }
 
 // Create JOIN clause string.
-func buildJoin(joins []Join, baseTable string) string {
+func buildJoin(compat Compat, joins []Join, baseTable interface{}) string {
 	if len(joins) == 0 {
 		return ""
 	}
 
 	join := ""
 	for _, j := range joins {
-		join += fmt.Sprintf(
-			" JOIN %s ON (%s.%s %s %s.%s)",
-			quote(j.table),
-			quote(baseTable),
-			quote(j.on.field),
-			string(j.on.comparison),
-			quote(j.table),
-			quote(j.on.value.(string)),
-		)
+		keyword := " JOIN"
+		if j.full {
+			keyword = " FULL OUTER JOIN"
+		}
+		join += fmt.Sprintf("%s %s ON (%s)", keyword, buildJoinTable(compat, j.table), buildJoinCondition(compat, j, baseTable))
 	}
 	return join
 }
 
+// Create joined table string, it has alias phrase if table is aliased
+func buildJoinTable(compat Compat, table interface{}) string {
+	if v, ok := table.(alias); ok {
+		return v.build(compat)
+	}
+	return quote(compat, schemaTable(table))
+}
+
+// Create JOIN condition string like "base.id = table.id".
+// Joined table is referred by alias name if table is aliased.
+func buildJoinCondition(compat Compat, j Join, baseTable interface{}) string {
+	joined := j.table
+	if v, ok := j.table.(alias); ok {
+		joined = aliasIdent(v.to)
+	}
+	return fmt.Sprintf(
+		"%s.%s %s %s.%s",
+		quote(compat, baseTable),
+		quote(compat, j.on.field),
+		string(j.on.comparison),
+		quote(compat, joined),
+		quote(compat, j.on.value),
+	)
+}
+
 // Create LIMIT clause string.
 func buildLimit(limit int64) string {
 	if limit == 0 {
@@ -144,14 +227,24 @@ func buildOffset(offset int64) string {
 }
 
 // Create GROUP BY clause string.
-func buildGroupBy(groupBy []string) string {
+func buildGroupBy(compat Compat, groupBy []interface{}) string {
 	if len(groupBy) == 0 {
 		return ""
 	}
 
 	var gb string
 	for _, g := range groupBy {
-		gb += quote(g) + ", "
+		gb += quote(compat, g) + ", "
 	}
 	return " GROUP BY " + strings.TrimRight(gb, ", ")
 }
+
+// Create whole raw query string with rewriting placeholders.
+func buildRawQuery(compat Compat, query string, binds []interface{}) (string, []interface{}, error) {
+	expr := newRawExpr(query, binds)
+	if expr.err != nil {
+		return "", nil, expr.err
+	}
+	query, binds = expr.buildCompat(compat, []interface{}{})
+	return query, binds, nil
+}
@@ -0,0 +1,191 @@
+package gqb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"database/sql"
+)
+
+// Execute bulk UPDATE query which updates rows by key column
+func (q *QueryBuilder) BulkUpdate(table interface{}, key string, data []Data) (sql.Result, error) {
+	return q.BulkUpdateContext(context.Background(), table, key, data)
+}
+
+// Execute bulk UPDATE query which updates rows by key column with context.
+// All rows must have the same keys including key column, and rows are split into multiple queries
+// when bind parameters exceed the driver's limit. Call Atomic() to run them in a single transaction.
+//
+// PostgreSQL uses "UPDATE ... FROM (VALUES ...)", and other drivers use "UPDATE ... SET col = CASE key WHEN ... END".
+func (q *QueryBuilder) BulkUpdateContext(ctx context.Context, table interface{}, key string, data []Data) (sql.Result, error) {
+	q = q.derive()
+	if data == nil {
+		return nil, fmt.Errorf("update data must be non-nil")
+	} else if len(data) == 0 {
+		return nil, fmt.Errorf("update data must not be empty")
+	} else if q.err != nil {
+		return nil, q.err
+	} else if q.maxAffected > 0 && !q.compat.Capabilities().AffectedRows {
+		return nil, fmt.Errorf("MaxAffected() is not supported because driver doesn't report affected rows")
+	}
+	mainTable, err := q.formatTable(table)
+	if err != nil {
+		return nil, err
+	}
+	wheres, err := q.conditions(ctx, table)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]Data, len(data))
+	for i, d := range data {
+		rows[i] = q.stampData(d, false)
+	}
+	keys := rows[0].Keys()
+	for i, d := range rows {
+		if _, ok := d[key]; !ok {
+			return nil, fmt.Errorf("update data at %d doesn't have key column %s", i, key)
+		} else if !sameKeys(keys, d.Keys()) {
+			return nil, fmt.Errorf("update data at %d has different keys from the first data", i)
+		} else if err := q.checkTenantData(ctx, table, d); err != nil {
+			return nil, err
+		}
+	}
+	if err := q.checkIdentifiers(keys...); err != nil {
+		return nil, err
+	}
+	columns := []string{}
+	for _, k := range keys {
+		if k != key {
+			columns = append(columns, k)
+		}
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("update data must have columns except key column")
+	}
+
+	fromValues := q.compat.Capabilities().BulkUpdate == BulkUpdateValues
+	// Determine number of rows in a query which doesn't exceed the limit of bind parameters
+	_, whereBinds := buildWhere(q.compat, wheres, []interface{}{})
+	perRow := len(columns)*2 + 1
+	if fromValues {
+		perRow = len(keys)
+	}
+	size := (q.compat.Capabilities().MaxBinds - len(whereBinds)) / perRow
+	if size <= 0 {
+		return nil, fmt.Errorf("update data has too many columns")
+	}
+
+	queries := []batchQuery{}
+	for start := 0; start < len(rows); start += size {
+		end := start + size
+		if end > len(rows) {
+			end = len(rows)
+		}
+		var bq batchQuery
+		if fromValues {
+			bq = buildBulkUpdateFrom(q.compat, table, mainTable, key, columns, rows[start:end], wheres)
+		} else {
+			bq = buildBulkUpdateCase(q.compat, mainTable, key, columns, rows[start:end], wheres)
+		}
+		queries = append(queries, bq)
+	}
+	defer q.Reset()
+	return q.execBatch(ctx, queries)
+}
+
+// Create bulk UPDATE query with CASE expression like:
+// UPDATE t SET col = CASE key WHEN ? THEN ? ... END WHERE key IN (...)
+// or ALTER TABLE t UPDATE col = CASE ... END WHERE key IN (...) on ClickHouse
+func buildBulkUpdateCase(compat Compat, mainTable, key string, columns []string, rows []Data, wheres []ConditionBuilder) batchQuery {
+	binds := []interface{}{}
+	updates := []string{}
+	for _, c := range columns {
+		phrase := quote(compat, c) + " = CASE " + quote(compat, key)
+		for _, d := range rows {
+			var value string
+			phrase += " WHEN " + compat.PlaceHolder(len(binds)+1)
+			binds = bind(compat, binds, d[key])
+			value, binds = bindValue(compat, binds, d[c])
+			phrase += " THEN " + value
+		}
+		updates = append(updates, phrase+" END")
+	}
+	keys := []interface{}{}
+	for _, d := range rows {
+		keys = append(keys, d[key])
+	}
+	where, binds := buildWhere(compat, restrictConditions(wheres, condition{
+		comparison: In,
+		field:      key,
+		value:      keys,
+		combine:    And,
+	}), binds)
+	format := "UPDATE %s SET %s%s"
+	if compat.Capabilities().Mutation == MutationAlterTable {
+		format = "ALTER TABLE %s UPDATE %s%s"
+	}
+	return batchQuery{
+		query: fmt.Sprintf(
+			format,
+			mainTable,
+			strings.Join(updates, ", "),
+			where,
+		),
+		binds: binds,
+	}
+}
+
+// Create bulk UPDATE query with VALUES list like:
+// UPDATE t SET col = v.col FROM (VALUES ...) AS v(...) WHERE t.key = v.key
+//
+// Bind parameters in VALUES list are resolved as text type, so the empty SELECT from the table precedes VALUES list
+// in order to resolve them as column types. Also columns of VALUES list are renamed to avoid ambiguous column in conditions.
+func buildBulkUpdateFrom(compat Compat, table interface{}, mainTable, key string, columns []string, rows []Data, wheres []ConditionBuilder) batchQuery {
+	target, source := mainTable, mainTable
+	if v, ok := table.(alias); ok {
+		target, source = quote(compat, aliasIdent(v.to)), quote(compat, schemaTable(v.from))
+	}
+	fields := append([]string{key}, columns...)
+	names := []string{}
+	selects := []string{}
+	for i, f := range fields {
+		names = append(names, quote(compat, fmt.Sprintf("gqb_%d", i)))
+		selects = append(selects, quote(compat, f))
+	}
+
+	binds := []interface{}{}
+	valueGroup := []string{}
+	for _, d := range rows {
+		values := []string{}
+		for _, f := range fields {
+			var value string
+			value, binds = bindValue(compat, binds, d[f])
+			values = append(values, value)
+		}
+		valueGroup = append(valueGroup, "("+strings.Join(values, ", ")+")")
+	}
+	updates := []string{}
+	for i, c := range columns {
+		updates = append(updates, quote(compat, c)+" = "+quote(compat, "v")+"."+names[i+1])
+	}
+	where, binds := buildWhere(compat, restrictConditions(wheres, rawCondition{
+		expr:    newRawExpr(target+"."+quote(compat, key)+" = "+quote(compat, "v")+"."+names[0], nil),
+		combine: And,
+	}), binds)
+	return batchQuery{
+		query: fmt.Sprintf(
+			"UPDATE %s SET %s FROM (SELECT %s FROM %s WHERE false UNION ALL VALUES %s) AS %s (%s)%s",
+			mainTable,
+			strings.Join(updates, ", "),
+			strings.Join(selects, ", "),
+			source,
+			strings.Join(valueGroup, ", "),
+			quote(compat, "v"),
+			strings.Join(names, ", "),
+			where,
+		),
+		binds: binds,
+	}
+}
@@ -0,0 +1,203 @@
+package gqb
+
+import (
+	"fmt"
+	"sync"
+)
+
+// ReturningStyle is how INSERT query returns inserted rows
+type ReturningStyle int
+
+const (
+	// ReturningNone indicates driver can't return inserted rows
+	ReturningNone ReturningStyle = iota
+	// ReturningClause appends "RETURNING cols" like PostgreSQL
+	ReturningClause
+	// ReturningOutput places "OUTPUT INSERTED.cols" before VALUES like SQL Server
+	ReturningOutput
+	// ReturningInto appends "RETURNING cols INTO" with out bind parameters like Oracle
+	ReturningInto
+)
+
+// UpsertStyle is how INSERT query updates existing row on conflict
+type UpsertStyle int
+
+const (
+	// UpsertNone indicates driver can't update existing row on INSERT
+	UpsertNone UpsertStyle = iota
+	// UpsertOnDuplicateKey appends "ON DUPLICATE KEY UPDATE" like MySQL
+	UpsertOnDuplicateKey
+	// UpsertOnConflict appends "ON CONFLICT (cols) DO UPDATE" like PostgreSQL
+	UpsertOnConflict
+	// UpsertStatement uses "UPSERT INTO" if conflict columns are not specified, otherwise "ON CONFLICT" like CockroachDB
+	UpsertStatement
+)
+
+// MutationStyle is how UPDATE/DELETE query is written
+type MutationStyle int
+
+const (
+	// MutationStandard uses "UPDATE t SET" and "DELETE FROM t"
+	MutationStandard MutationStyle = iota
+	// MutationAlterTable uses "ALTER TABLE t UPDATE" and "ALTER TABLE t DELETE" like ClickHouse
+	MutationAlterTable
+)
+
+// JoinMutationStyle is how UPDATE/DELETE query joins other tables
+type JoinMutationStyle int
+
+const (
+	// JoinMutationNone indicates driver can't join tables on UPDATE/DELETE query
+	JoinMutationNone JoinMutationStyle = iota
+	// JoinMutationInline uses "UPDATE t JOIN j ON ... SET" and "DELETE t FROM t JOIN j ON ..." like MySQL
+	JoinMutationInline
+	// JoinMutationFrom uses "UPDATE t SET ... FROM j" and "DELETE FROM t USING j" like PostgreSQL
+	JoinMutationFrom
+)
+
+// BulkUpdateStyle is how BulkUpdate() writes query
+type BulkUpdateStyle int
+
+const (
+	// BulkUpdateCase uses "SET col = CASE key WHEN ? THEN ? END"
+	BulkUpdateCase BulkUpdateStyle = iota
+	// BulkUpdateValues joins "VALUES" list as derived table like PostgreSQL
+	BulkUpdateValues
+)
+
+// BulkInsertStyle is how BulkInsert() writes multiple rows
+type BulkInsertStyle int
+
+const (
+	// BulkInsertValues uses multi-row "VALUES (...), (...)"
+	BulkInsertValues BulkInsertStyle = iota
+	// BulkInsertAll uses "INSERT ALL INTO t VALUES (...) INTO t VALUES (...) SELECT 1 FROM dual" like Oracle
+	BulkInsertAll
+)
+
+// LockClause is row locking clause of SELECT query
+type LockClause string
+
+const (
+	LockForUpdate           LockClause = "FOR UPDATE"
+	LockForShare            LockClause = "FOR SHARE"
+	LockForUpdateNoWait     LockClause = "FOR UPDATE NOWAIT"
+	LockForUpdateSkipLocked LockClause = "FOR UPDATE SKIP LOCKED"
+)
+
+// Capabilities describes what the dialect can express.
+// Builder methods consult it in order to write dialect specific query, or to return error instead of query which the server rejects.
+type Capabilities struct {
+	// MaxBinds is maximum number of bind parameters in one statement
+	MaxBinds int
+	// MaxInsertRows is maximum number of rows in VALUES list of INSERT query, zero means no limit
+	MaxInsertRows int
+
+	Returning  ReturningStyle
+	Upsert     UpsertStyle
+	BulkInsert BulkInsertStyle
+	BulkUpdate BulkUpdateStyle
+
+	Mutation     MutationStyle
+	MutationJoin JoinMutationStyle
+	// MutationLimit indicates UPDATE/DELETE query accepts ORDER BY and LIMIT
+	MutationLimit bool
+	// RowID is row identifier column like "ctid", UPDATE/DELETE query with joins or limit is emulated by subquery with it
+	RowID string
+	// OffsetRequiresLimit indicates OFFSET clause requires LIMIT clause, so "LIMIT -1" is written for no limit like SQLite
+	OffsetRequiresLimit bool
+
+	// FullJoin indicates FULL OUTER JOIN is available
+	FullJoin bool
+	// RowValue indicates row value comparison like "(a, b) > (?, ?)" is available
+	RowValue bool
+	// LockClauses are available row locking clauses
+	LockClauses []LockClause
+	// LockWithoutLimit indicates locking clause can't be used with limit and offset like Oracle
+	LockWithoutLimit bool
+
+	// Transaction indicates statements can be run in transaction atomically
+	Transaction bool
+	// AffectedRows indicates UPDATE/DELETE query reports affected rows synchronously
+	AffectedRows bool
+	// TableAliasWithoutAs indicates table alias is written without AS keyword like Oracle
+	TableAliasWithoutAs bool
+
+	// AsOfSystemTime indicates historical read with "AS OF SYSTEM TIME" is available
+	AsOfSystemTime bool
+	// TableModifiers indicates FINAL and SAMPLE modifiers of ClickHouse are available
+	TableModifiers bool
+	// LimitBy indicates "LIMIT n BY cols" of ClickHouse is available
+	LimitBy bool
+
+	// Loader is default loader for BulkLoad(), InsertLoader is used if nil
+	Loader Loader
+}
+
+// Supports returns true if capabilities cover the feature
+func (c Capabilities) Supports(f Feature) bool {
+	switch f {
+	case FeatureReturning:
+		return c.Returning == ReturningClause || c.Returning == ReturningOutput
+	case FeatureUpsert:
+		return c.Upsert != UpsertNone
+	case FeatureRowValue:
+		return c.RowValue
+	case FeatureAsOfSystemTime:
+		return c.AsOfSystemTime
+	}
+	return false
+}
+
+// Check lock clause is available
+func (c Capabilities) supportsLock(l LockClause) bool {
+	for _, v := range c.LockClauses {
+		if v == l {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	dialectsMu sync.RWMutex
+	dialects   = map[string]Compat{
+		"mysql":       MysqlCompat{},
+		"postgres":    PostgresCompat{},
+		"pq":          PostgresCompat{Copy: true},
+		"sqlite":      SQLiteCompat{},
+		"sqlserver":   SQLServerCompat{},
+		"mssql":       SQLServerCompat{},
+		"oracle":      OracleCompat{},
+		"oracle11":    OracleCompat{RowNum: true},
+		"clickhouse":  ClickHouseCompat{},
+		"mariadb":     MariaDBCompat{},
+		"mariadb10.5": MariaDBCompat{Returning: true},
+		"cockroach":   CockroachCompat{},
+		"cockroachdb": CockroachCompat{},
+		"yugabyte":    YugabyteCompat{},
+		"yugabytedb":  YugabyteCompat{},
+	}
+)
+
+// Register dialect with name in order to be used by SetDriver().
+// Registering existing name replaces the dialect, so builtin dialect can be customized like OracleCompat{RowNum: true}.
+func RegisterDialect(name string, c Compat) {
+	if c == nil {
+		panic("gqb: RegisterDialect compat is nil")
+	}
+	dialectsMu.Lock()
+	defer dialectsMu.Unlock()
+	dialects[name] = c
+}
+
+// Find registered dialect by name
+func lookupDialect(name string) (Compat, error) {
+	dialectsMu.RLock()
+	defer dialectsMu.RUnlock()
+	c, ok := dialects[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown driver %s, register it by RegisterDialect()", name)
+	}
+	return c, nil
+}
@@ -0,0 +1,99 @@
+package gqb
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// limitBy is "LIMIT n BY fields" clause of ClickHouse
+type limitBy struct {
+	limit  int64
+	fields []interface{}
+}
+
+// Read from ClickHouse table with FINAL modifier in order to merge rows before selecting
+func (q *QueryBuilder) Final() *QueryBuilder {
+	q = q.derive()
+	q.final = true
+	return q
+}
+
+// Read sampled data from ClickHouse table with SAMPLE modifier.
+// ratio accepts relative coefficient like 0.1, or approximate number of rows like 10000.
+func (q *QueryBuilder) Sample(ratio float64) *QueryBuilder {
+	q = q.derive()
+	if ratio <= 0 && q.err == nil {
+		q.err = fmt.Errorf("sample ratio must be greater than zero")
+	}
+	q.sample = ratio
+	return q
+}
+
+// Add "LIMIT n BY fields" clause of ClickHouse which limits rows for each group of fields
+func (q *QueryBuilder) LimitBy(limit int64, fields ...interface{}) *QueryBuilder {
+	q = q.derive()
+	if (limit <= 0 || len(fields) == 0) && q.err == nil {
+		q.err = fmt.Errorf("LimitBy() requires positive limit and at least one field")
+	}
+	for _, f := range fields {
+		if err := checkIdentType(f); err != nil && q.err == nil {
+			q.err = err
+		}
+	}
+	q.limitBy = &limitBy{
+		limit:  limit,
+		fields: fields,
+	}
+	return q
+}
+
+// Create FINAL and SAMPLE modifiers which are placed after table name
+func (q *QueryBuilder) buildTableModifiers() (string, error) {
+	if !q.final && q.sample == 0 {
+		return "", nil
+	} else if !q.compat.Capabilities().TableModifiers {
+		return "", fmt.Errorf("FINAL and SAMPLE modifiers are not supported on this driver")
+	}
+	var modifiers string
+	if q.final {
+		modifiers += " FINAL"
+	}
+	if q.sample > 0 {
+		modifiers += " SAMPLE " + strconv.FormatFloat(q.sample, 'f', -1, 64)
+	}
+	return modifiers, nil
+}
+
+// Create "LIMIT n BY fields" clause
+func (q *QueryBuilder) buildLimitBy() (string, error) {
+	if q.limitBy == nil {
+		return "", nil
+	} else if !q.compat.Capabilities().LimitBy {
+		return "", fmt.Errorf("LIMIT BY clause is not supported on this driver")
+	}
+	fields := []string{}
+	for _, f := range q.limitBy.fields {
+		fields = append(fields, quote(q.compat, f))
+	}
+	return fmt.Sprintf(" LIMIT %d BY %s", q.limitBy.limit, strings.Join(fields, ", ")), nil
+}
+
+// Create mutation query like "ALTER TABLE t UPDATE ... WHERE ..." or "ALTER TABLE t DELETE WHERE ..." of ClickHouse.
+// ClickHouse runs mutation asynchronously and doesn't report affected rows,
+// so joins, orders, limit, offset, table alias and affected rows guard are refused.
+func (q *QueryBuilder) buildAlterMutation(table interface{}, mainTable, action string, wheres []ConditionBuilder, binds []interface{}) (string, []interface{}, error) {
+	if len(q.joins) > 0 || q.needsRowSubquery() {
+		return "", nil, fmt.Errorf("JOIN, ORDER BY, LIMIT and OFFSET are not supported on ALTER TABLE mutation")
+	} else if _, ok := table.(alias); ok {
+		return "", nil, fmt.Errorf("table alias is not supported on ALTER TABLE mutation")
+	} else if q.maxAffected > 0 && !q.compat.Capabilities().AffectedRows {
+		return "", nil, fmt.Errorf("MaxAffected() is not supported because driver doesn't report affected rows")
+	}
+	where, binds := buildWhere(q.compat, wheres, binds)
+	// ClickHouse requires WHERE clause for mutation, so full table mutation uses always true condition
+	if where == "" {
+		where = " WHERE 1"
+	}
+	return fmt.Sprintf("ALTER TABLE %s %s%s", mainTable, action, where), binds, nil
+}
@@ -0,0 +1,115 @@
+package gqb_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ysugimoto/gqb"
+)
+
+func runClickHouseTest(t *testing.T) {
+	gqb.SetDriver("clickhouse")
+
+	t.Run("Where() with backtick quoting", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			Select("events.id", "name").
+			Where("id", 1, gqb.Equal).
+			Get("events")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, "SELECT `events`.`id`, `name` FROM `events` WHERE (`id` = ?)", m.query)
+		assert.Equal(t, []interface{}{1}, m.binds)
+	})
+
+	t.Run("OrderBy() with random", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			OrderBy("id", gqb.Rand).
+			Get("events")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, "SELECT * FROM `events` ORDER BY rand()", m.query)
+	})
+
+	t.Run("Final(), Sample() and LimitBy() build modifiers", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			Final().
+			Sample(0.1).
+			Where("type", "click", gqb.Equal).
+			OrderBy("created_at", gqb.Desc).
+			LimitBy(3, "user_id").
+			Limit(100).
+			Get("events")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, "SELECT * FROM `events` FINAL SAMPLE 0.1 WHERE (`type` = ?) ORDER BY `created_at` DESC LIMIT 3 BY `user_id` LIMIT 100", m.query)
+	})
+
+	t.Run("SelectCount() and LimitBy() accept Ident", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			Select("user.id").
+			SelectCount(gqb.Ident{"event.id"}).
+			LimitBy(3, gqb.Ident{"user.id"}).
+			Get("events")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, "SELECT `user`.`id`, COUNT(`event.id`) FROM `events` LIMIT 3 BY `user.id`", m.query)
+	})
+
+	t.Run("Update query uses ALTER TABLE UPDATE", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			Where("id", 1, gqb.Equal).
+			Update("events", gqb.Data{"name": "view"})
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, "ALTER TABLE `events` UPDATE `name` = ? WHERE (`id` = ?)", m.query)
+		assert.Equal(t, []interface{}{"view", 1}, m.binds)
+	})
+
+	t.Run("Delete query uses ALTER TABLE DELETE", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			AllowFullTable().
+			Delete("events")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, "ALTER TABLE `events` DELETE WHERE 1", m.query)
+	})
+
+	t.Run("Delete query with LIMIT returns error", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			Where("id", 1, gqb.Equal).
+			Limit(1).
+			Delete("events")
+		assert.Error(t, err)
+		assert.Equal(t, "", m.query)
+	})
+
+	t.Run("OptimisticLock() returns error", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m, gqb.OptimisticLock("version")).
+			Where("id", 1, gqb.Equal).
+			Update("events", gqb.Data{"name": "view", "version": 1})
+		assert.Error(t, err)
+		assert.Equal(t, "", m.query)
+	})
+
+	t.Run("InsertReturning() returns error", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			InsertReturning(context.Background(), "events", gqb.Data{"name": "view"})
+		assert.Error(t, err)
+		assert.Equal(t, "", m.query)
+	})
+
+	t.Run("BulkUpdate query uses ALTER TABLE UPDATE", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			BulkUpdate("events", "id", []gqb.Data{
+				gqb.Data{"id": 1, "name": "view"},
+				gqb.Data{"id": 2, "name": "click"},
+			})
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, "ALTER TABLE `events` UPDATE `name` = CASE `id` WHEN ? THEN ? WHEN ? THEN ? END WHERE (`id` IN (?, ?))", m.query)
+	})
+}
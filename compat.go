@@ -3,26 +3,50 @@ package gqb
 import (
 	"fmt"
 	"strings"
+	"time"
+)
+
+// Feature is optional SQL feature which depends on driver
+type Feature int
+
+const (
+	// FeatureReturning indicates INSERT query can return inserted rows
+	FeatureReturning Feature = iota
+	// FeatureUpsert indicates INSERT query can update row on conflict
+	FeatureUpsert
+	// FeatureRowValue indicates row value comparison like "(a, b) > (?, ?)" is available
+	FeatureRowValue
+	// FeatureAsOfSystemTime indicates historical read with "AS OF SYSTEM TIME" is available
+	FeatureAsOfSystemTime
 )
 
 type Compat interface {
 	Quote(string) string
 	RandFunc() string
 	PlaceHolder(int) string
+	// LimitOffset returns LIMIT and OFFSET phrase, ordered indicates query has ORDER BY clause
+	LimitOffset(limit, offset int64, ordered bool) (string, error)
+	// FormatTime returns bind parameter for time value, layout is format for the column type
+	FormatTime(t time.Time, layout string) interface{}
+	// Capabilities returns what the dialect can express
+	Capabilities() Capabilities
+}
+
+// limitWrapper is optional interface for dialect which paginates by wrapping query like ROWNUM of Oracle.
+// It is called after LimitOffset() phrase is appended.
+type limitWrapper interface {
+	WrapLimitOffset(query string, limit, offset int64) string
 }
 
 type MysqlCompat struct {
 }
 
 func (c MysqlCompat) Quote(str string) string {
-	if str == "" {
-		return str
-	}
-	split := strings.Split(str, ".")
-	for i, _ := range split {
-		split[i] = "`" + strings.Trim(split[i], "`") + "`"
-	}
-	return strings.Join(split, ".")
+	return quoteIdentifier(str, "`", "`", nil)
+}
+
+func (c MysqlCompat) QuoteIdent(part string) string {
+	return quotePart(part, "`", "`")
 }
 
 func (c MysqlCompat) RandFunc() string {
@@ -33,18 +57,39 @@ func (c MysqlCompat) PlaceHolder(index int) string {
 	return "?"
 }
 
+func (c MysqlCompat) LimitOffset(limit, offset int64, ordered bool) (string, error) {
+	return buildLimit(limit) + buildOffset(offset), nil
+}
+
+func (c MysqlCompat) FormatTime(t time.Time, layout string) interface{} {
+	return t.Format(layout)
+}
+
+func (c MysqlCompat) Capabilities() Capabilities {
+	return Capabilities{
+		MaxBinds:      65535,
+		Upsert:        UpsertOnDuplicateKey,
+		MutationJoin:  JoinMutationInline,
+		MutationLimit: true,
+		RowValue:      true,
+		LockClauses:   []LockClause{LockForUpdate, LockForShare, LockForUpdateNoWait, LockForUpdateSkipLocked},
+		Transaction:   true,
+		AffectedRows:  true,
+	}
+}
+
+// PostgresCompat is compat for PostgreSQL.
+// BulkLoad() uses chunked bulk INSERT, set Copy to use "COPY FROM STDIN" with the driver which supports it through prepared statement like lib/pq.
 type PostgresCompat struct {
+	Copy bool
 }
 
 func (c PostgresCompat) Quote(str string) string {
-	if str == "" {
-		return str
-	}
-	split := strings.Split(str, ".")
-	for i, _ := range split {
-		split[i] = `"` + strings.Trim(split[i], `"`) + `"`
-	}
-	return strings.Join(split, ".")
+	return quoteIdentifier(str, `"`, `"`, nil)
+}
+
+func (c PostgresCompat) QuoteIdent(part string) string {
+	return quotePart(part, `"`, `"`)
 }
 
 func (c PostgresCompat) RandFunc() string {
@@ -55,18 +100,43 @@ func (c PostgresCompat) PlaceHolder(index int) string {
 	return fmt.Sprintf("$%d", index)
 }
 
-type SQLiteCompat struct {
+func (c PostgresCompat) LimitOffset(limit, offset int64, ordered bool) (string, error) {
+	return buildLimit(limit) + buildOffset(offset), nil
 }
 
-func (c SQLiteCompat) Quote(str string) string {
-	if str == "" {
-		return str
+func (c PostgresCompat) FormatTime(t time.Time, layout string) interface{} {
+	return t.Format(layout)
+}
+
+func (c PostgresCompat) Capabilities() Capabilities {
+	caps := Capabilities{
+		MaxBinds:     65535,
+		Returning:    ReturningClause,
+		Upsert:       UpsertOnConflict,
+		BulkUpdate:   BulkUpdateValues,
+		MutationJoin: JoinMutationFrom,
+		RowID:        "ctid",
+		FullJoin:     true,
+		RowValue:     true,
+		LockClauses:  []LockClause{LockForUpdate, LockForShare, LockForUpdateNoWait, LockForUpdateSkipLocked},
+		Transaction:  true,
+		AffectedRows: true,
 	}
-	split := strings.Split(str, ".")
-	for i, _ := range split {
-		split[i] = `"` + strings.Trim(split[i], `"`) + `"`
+	if c.Copy {
+		caps.Loader = PostgresCopyLoader{}
 	}
-	return strings.Join(split, ".")
+	return caps
+}
+
+type SQLiteCompat struct {
+}
+
+func (c SQLiteCompat) Quote(str string) string {
+	return quoteIdentifier(str, `"`, `"`, nil)
+}
+
+func (c SQLiteCompat) QuoteIdent(part string) string {
+	return quotePart(part, `"`, `"`)
 }
 
 func (c SQLiteCompat) RandFunc() string {
@@ -76,3 +146,263 @@ func (c SQLiteCompat) RandFunc() string {
 func (c SQLiteCompat) PlaceHolder(index int) string {
 	return "?"
 }
+
+func (c SQLiteCompat) LimitOffset(limit, offset int64, ordered bool) (string, error) {
+	return buildLimit(limit) + buildOffset(offset), nil
+}
+
+func (c SQLiteCompat) FormatTime(t time.Time, layout string) interface{} {
+	return t.Format(layout)
+}
+
+func (c SQLiteCompat) Capabilities() Capabilities {
+	return Capabilities{
+		// SQLite limits 999 bind parameters before 3.32.0, so use conservative number
+		MaxBinds:            999,
+		Returning:           ReturningClause,
+		Upsert:              UpsertOnConflict,
+		RowID:               "rowid",
+		OffsetRequiresLimit: true,
+		RowValue:            true,
+		Transaction:         true,
+		AffectedRows:        true,
+	}
+}
+
+type SQLServerCompat struct {
+}
+
+func (c SQLServerCompat) Quote(str string) string {
+	return quoteIdentifier(str, "[", "]", nil)
+}
+
+func (c SQLServerCompat) QuoteIdent(part string) string {
+	return quotePart(part, "[", "]")
+}
+
+func (c SQLServerCompat) RandFunc() string {
+	return "NEWID()"
+}
+
+func (c SQLServerCompat) PlaceHolder(index int) string {
+	return fmt.Sprintf("@p%d", index)
+}
+
+// SQL Server paginates with "OFFSET n ROWS FETCH NEXT n ROWS ONLY" which requires ORDER BY clause
+func (c SQLServerCompat) LimitOffset(limit, offset int64, ordered bool) (string, error) {
+	if limit == 0 && offset == 0 {
+		return "", nil
+	}
+	// OFFSET FETCH requires ORDER BY clause, so rows are fetched in undefined order without it
+	phrase := fmt.Sprintf(" OFFSET %d ROWS", offset)
+	if !ordered {
+		phrase = " ORDER BY (SELECT NULL)" + phrase
+	}
+	if limit > 0 {
+		phrase += fmt.Sprintf(" FETCH NEXT %d ROWS ONLY", limit)
+	}
+	return phrase, nil
+}
+
+func (c SQLServerCompat) FormatTime(t time.Time, layout string) interface{} {
+	return t.Format(layout)
+}
+
+func (c SQLServerCompat) Capabilities() Capabilities {
+	return Capabilities{
+		// SQL Server accepts up to 2100 parameters in one request
+		MaxBinds:      2100,
+		MaxInsertRows: 1000,
+		Returning:     ReturningOutput,
+		FullJoin:      true,
+		Transaction:   true,
+		AffectedRows:  true,
+	}
+}
+
+// OracleCompat is compat for Oracle Database.
+// Identifiers are quoted with upper case because unquoted identifiers are resolved as upper case.
+// Pagination uses "FETCH FIRST" which is available since 12c, set RowNum to use ROWNUM for older versions.
+type OracleCompat struct {
+	RowNum bool
+}
+
+// Unquoted identifier is converted to upper case as Oracle does, and quoted one is kept as it is
+func (c OracleCompat) Quote(str string) string {
+	return quoteIdentifier(str, `"`, `"`, strings.ToUpper)
+}
+
+func (c OracleCompat) QuoteIdent(part string) string {
+	return quotePart(part, `"`, `"`)
+}
+
+func (c OracleCompat) RandFunc() string {
+	return "DBMS_RANDOM.VALUE"
+}
+
+func (c OracleCompat) PlaceHolder(index int) string {
+	return fmt.Sprintf(":%d", index)
+}
+
+// ROWNUM pagination is applied by wrapping query, see WrapLimitOffset()
+func (c OracleCompat) LimitOffset(limit, offset int64, ordered bool) (string, error) {
+	if c.RowNum || (limit == 0 && offset == 0) {
+		return "", nil
+	}
+	phrase := ""
+	if offset > 0 {
+		phrase += fmt.Sprintf(" OFFSET %d ROWS", offset)
+	}
+	if limit > 0 && offset > 0 {
+		phrase += fmt.Sprintf(" FETCH NEXT %d ROWS ONLY", limit)
+	} else if limit > 0 {
+		phrase += fmt.Sprintf(" FETCH FIRST %d ROWS ONLY", limit)
+	}
+	return phrase, nil
+}
+
+// Oracle interprets string as DATE/TIMESTAMP by session's NLS format, so time is bound as it is
+func (c OracleCompat) FormatTime(t time.Time, layout string) interface{} {
+	return t
+}
+
+func (c OracleCompat) Capabilities() Capabilities {
+	return Capabilities{
+		MaxBinds:            65535,
+		Returning:           ReturningInto,
+		BulkInsert:          BulkInsertAll,
+		FullJoin:            true,
+		LockClauses:         []LockClause{LockForUpdate, LockForUpdateNoWait, LockForUpdateSkipLocked},
+		LockWithoutLimit:    true,
+		Transaction:         true,
+		AffectedRows:        true,
+		TableAliasWithoutAs: true,
+	}
+}
+
+// limitWrapper interface implementation, wrap query by ROWNUM subquery for pagination
+func (c OracleCompat) WrapLimitOffset(query string, limit, offset int64) string {
+	if !c.RowNum || (limit == 0 && offset == 0) {
+		return query
+	} else if offset == 0 {
+		return fmt.Sprintf("SELECT * FROM (%s) WHERE ROWNUM <= %d", query, limit)
+	}
+	inner := fmt.Sprintf("SELECT %s.*, ROWNUM %s FROM (%s) %s", c.Quote("gqb_q"), c.Quote("gqb_rn"), query, c.Quote("gqb_q"))
+	if limit > 0 {
+		inner += fmt.Sprintf(" WHERE ROWNUM <= %d", offset+limit)
+	}
+	return fmt.Sprintf("SELECT * FROM (%s) WHERE %s > %d", inner, c.Quote("gqb_rn"), offset)
+}
+
+type ClickHouseCompat struct {
+}
+
+func (c ClickHouseCompat) Quote(str string) string {
+	return quoteIdentifier(str, "`", "`", nil)
+}
+
+func (c ClickHouseCompat) QuoteIdent(part string) string {
+	return quotePart(part, "`", "`")
+}
+
+func (c ClickHouseCompat) RandFunc() string {
+	return "rand()"
+}
+
+func (c ClickHouseCompat) PlaceHolder(index int) string {
+	return "?"
+}
+
+func (c ClickHouseCompat) LimitOffset(limit, offset int64, ordered bool) (string, error) {
+	return buildLimit(limit) + buildOffset(offset), nil
+}
+
+func (c ClickHouseCompat) FormatTime(t time.Time, layout string) interface{} {
+	return t.Format(layout)
+}
+
+func (c ClickHouseCompat) Capabilities() Capabilities {
+	return Capabilities{
+		// ClickHouse prefers large insert blocks and driver interpolates bind parameters on client side,
+		// so allow many bind parameters in order to avoid splitting insert into small parts
+		MaxBinds:       1 << 20,
+		Mutation:       MutationAlterTable,
+		FullJoin:       true,
+		RowValue:       true,
+		TableModifiers: true,
+		LimitBy:        true,
+		Loader:         ClickHouseBatchLoader{},
+	}
+}
+
+// MariaDBCompat is compat for MariaDB which is MySQL compatible.
+// RETURNING clause of INSERT is available since MariaDB 10.5, set Returning to use it on 10.5 or later.
+type MariaDBCompat struct {
+	MysqlCompat
+	Returning bool
+}
+
+func (c MariaDBCompat) Capabilities() Capabilities {
+	caps := c.MysqlCompat.Capabilities()
+	if c.Returning {
+		caps.Returning = ReturningClause
+	}
+	caps.LockClauses = []LockClause{LockForUpdate, LockForUpdateNoWait, LockForUpdateSkipLocked}
+	return caps
+}
+
+// CockroachCompat is compat for CockroachDB which is PostgreSQL wire compatible.
+// CockroachDB supports "UPSERT" statement and historical read with "AS OF SYSTEM TIME",
+// and supports ORDER BY and LIMIT on UPDATE/DELETE instead of ctid.
+type CockroachCompat struct {
+	PostgresCompat
+}
+
+func (c CockroachCompat) Capabilities() Capabilities {
+	caps := c.PostgresCompat.Capabilities()
+	caps.Upsert = UpsertStatement
+	caps.MutationLimit = true
+	caps.RowID = ""
+	caps.AsOfSystemTime = true
+	return caps
+}
+
+// YugabyteCompat is compat for YugabyteDB which is PostgreSQL wire compatible.
+// YugabyteDB doesn't have ctid, so UPDATE/DELETE query with limit is not available.
+type YugabyteCompat struct {
+	PostgresCompat
+}
+
+func (c YugabyteCompat) Capabilities() Capabilities {
+	caps := c.PostgresCompat.Capabilities()
+	caps.RowID = ""
+	return caps
+}
+
+// Create phrases for returning inserted rows, output is placed before VALUES and suffix is placed at the end of query
+func buildReturning(compat Compat, columns []string) (output, suffix string, err error) {
+	switch compat.Capabilities().Returning {
+	case ReturningClause:
+		return "", " RETURNING " + buildReturningFields(compat, columns, ""), nil
+	case ReturningOutput:
+		return " OUTPUT " + buildReturningFields(compat, columns, "INSERTED"), "", nil
+	case ReturningInto:
+		return "", "", fmt.Errorf("driver returns inserted values through out bind parameters, use InsertReturningInto() instead")
+	}
+	return "", "", fmt.Errorf("returning inserted rows is not supported on this driver")
+}
+
+// Create field list for returning phrase, prefix is pseudo table name like "INSERTED" if needed
+func buildReturningFields(compat Compat, columns []string, prefix string) string {
+	if prefix != "" {
+		prefix += "."
+	}
+	if len(columns) == 0 {
+		return prefix + "*"
+	}
+	fields := []string{}
+	for _, c := range columns {
+		fields = append(fields, prefix+quote(compat, c))
+	}
+	return strings.Join(fields, ", ")
+}
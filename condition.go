@@ -3,12 +3,13 @@ package gqb
 import (
 	"fmt"
 	"strings"
+	"time"
 )
 
 // Condition is common condition struct
 type condition struct {
 	comparison Comparison
-	field      string
+	field      interface{}
 	value      interface{}
 	combine    CombineType
 }
@@ -20,6 +21,16 @@ func (c condition) Combine() string {
 
 // conditionBuilder::Build() interface implementation
 func (c condition) Build(binds []interface{}) (string, []interface{}) {
+	return c.buildCompat(defaultDialect(), binds)
+}
+
+// Check field can be quoted
+func (c condition) validate() error {
+	return checkIdentType(c.field)
+}
+
+// compatBuilder::buildCompat() interface implementation
+func (c condition) buildCompat(compat Compat, binds []interface{}) (string, []interface{}) {
 	var clause string
 
 	switch c.comparison {
@@ -28,36 +39,36 @@ func (c condition) Build(binds []interface{}) (string, []interface{}) {
 		values, ok := c.value.([]interface{})
 		if ok {
 			for _, v := range values {
-				q += driverCompat.PlaceHolder(len(binds)+1) + ", "
-				binds = bind(binds, v)
+				q += compat.PlaceHolder(len(binds)+1) + ", "
+				binds = bind(compat, binds, v)
 			}
-			clause = fmt.Sprintf("%s IN (%s)", quote(c.field), strings.Trim(q, ", "))
+			clause = fmt.Sprintf("%s IN (%s)", quote(compat, c.field), strings.Trim(q, ", "))
 		}
 	case Equal:
 		if c.value == nil {
-			clause = fmt.Sprintf("%s IS NULL", quote(c.field))
+			clause = fmt.Sprintf("%s IS NULL", quote(compat, c.field))
 		} else {
-			clause = fmt.Sprintf("%s %s %s", quote(c.field), string(c.comparison), driverCompat.PlaceHolder(len(binds)+1))
-			binds = bind(binds, c.value)
+			clause = fmt.Sprintf("%s %s %s", quote(compat, c.field), string(c.comparison), compat.PlaceHolder(len(binds)+1))
+			binds = bind(compat, binds, c.value)
 		}
 	case NotEqual:
 		if c.value == nil {
-			clause = fmt.Sprintf("%s IS NOT NULL", quote(c.field))
+			clause = fmt.Sprintf("%s IS NOT NULL", quote(compat, c.field))
 		} else {
-			clause = fmt.Sprintf("%s %s %s", quote(c.field), string(c.comparison), driverCompat.PlaceHolder(len(binds)+1))
-			binds = bind(binds, c.value)
+			clause = fmt.Sprintf("%s %s %s", quote(compat, c.field), string(c.comparison), compat.PlaceHolder(len(binds)+1))
+			binds = bind(compat, binds, c.value)
 		}
 	default:
-		clause = fmt.Sprintf("%s %s %s", quote(c.field), string(c.comparison), driverCompat.PlaceHolder(len(binds)+1))
-		binds = bind(binds, c.value)
+		clause = fmt.Sprintf("%s %s %s", quote(compat, c.field), string(c.comparison), compat.PlaceHolder(len(binds)+1))
+		binds = bind(compat, binds, c.value)
 	}
 	return clause, binds
 }
 
 // Raw clause condition
 type rawCondition struct {
-	rawClause string
-	combine   CombineType
+	expr    RawExpr
+	combine CombineType
 }
 
 // conditionBuilder::getCombine() interface implementation
@@ -67,5 +78,112 @@ func (r rawCondition) Combine() string {
 
 // conditionBuilder::Build() interface implementation
 func (r rawCondition) Build(binds []interface{}) (string, []interface{}) {
-	return r.rawClause, binds
+	return r.buildCompat(defaultDialect(), binds)
+}
+
+// compatBuilder::buildCompat() interface implementation
+func (r rawCondition) buildCompat(compat Compat, binds []interface{}) (string, []interface{}) {
+	return r.expr.buildCompat(compat, binds)
+}
+
+// Return parse error of raw clause
+func (r rawCondition) validate() error {
+	return r.expr.err
+}
+
+// Condition list which wraps multiple conditions with parentheses
+type conditionList struct {
+	conditions []ConditionBuilder
+	combine    CombineType
+}
+
+// conditionBuilder::getCombine() interface implementation
+func (l conditionList) Combine() string {
+	return string(l.combine)
+}
+
+// conditionBuilder::Build() interface implementation
+func (l conditionList) Build(binds []interface{}) (string, []interface{}) {
+	return l.buildCompat(defaultDialect(), binds)
+}
+
+// compatBuilder::buildCompat() interface implementation
+func (l conditionList) buildCompat(compat Compat, binds []interface{}) (string, []interface{}) {
+	return buildConditions(compat, l.conditions, binds)
+}
+
+// Keyset condition for cursor pagination.
+// This condition compares sort keys with values of the last row,
+// with row value comparison like "(a, b) > (?, ?)" or expanded OR chain like "(a > ?) OR (a = ? AND b > ?)".
+type keysetCondition struct {
+	orders  []Order
+	values  []interface{}
+	reverse bool
+	combine CombineType
+}
+
+// conditionBuilder::getCombine() interface implementation
+func (k keysetCondition) Combine() string {
+	return string(k.combine)
+}
+
+// Get comparison operator for sort key
+func (k keysetCondition) comparison(o Order) string {
+	if (o.sort == Desc) != k.reverse {
+		return "<"
+	}
+	return ">"
+}
+
+// conditionBuilder::Build() interface implementation
+func (k keysetCondition) Build(binds []interface{}) (string, []interface{}) {
+	return k.buildCompat(defaultDialect(), binds)
+}
+
+// compatBuilder::buildCompat() interface implementation
+func (k keysetCondition) buildCompat(compat Compat, binds []interface{}) (string, []interface{}) {
+	uniform := true
+	for _, o := range k.orders {
+		if o.sort != k.orders[0].sort {
+			uniform = false
+		}
+	}
+
+	// Row value comparison is available only when all sort keys have the same direction
+	if uniform && compat.Capabilities().Supports(FeatureRowValue) {
+		fields := []string{}
+		values := []string{}
+		for i, o := range k.orders {
+			fields = append(fields, quote(compat, o.field))
+			values = append(values, compat.PlaceHolder(len(binds)+1))
+			binds = bindCursor(compat, binds, k.values[i])
+		}
+		return fmt.Sprintf(
+			"(%s) %s (%s)",
+			strings.Join(fields, ", "),
+			k.comparison(k.orders[0]),
+			strings.Join(values, ", "),
+		), binds
+	}
+
+	chain := []string{}
+	for i, o := range k.orders {
+		phrases := []string{}
+		for j := 0; j < i; j++ {
+			phrases = append(phrases, quote(compat, k.orders[j].field)+" = "+compat.PlaceHolder(len(binds)+1))
+			binds = bindCursor(compat, binds, k.values[j])
+		}
+		phrases = append(phrases, quote(compat, o.field)+" "+k.comparison(o)+" "+compat.PlaceHolder(len(binds)+1))
+		binds = bindCursor(compat, binds, k.values[i])
+		chain = append(chain, "("+strings.Join(phrases, " AND ")+")")
+	}
+	return strings.Join(chain, " OR "), binds
+}
+
+// Add sort key value of cursor to bind parameters, time value keeps sub-second precision
+func bindCursor(compat Compat, b []interface{}, v interface{}) []interface{} {
+	if t, ok := v.(time.Time); ok {
+		return append(b, compat.FormatTime(t, datetimeFormat+".999999999"))
+	}
+	return bind(compat, b, v)
 }
@@ -0,0 +1,87 @@
+package gqb
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"sync"
+)
+
+var (
+	driverTypesMu sync.RWMutex
+	// driverTypes maps driver type like "github.com/lib/pq.Driver" to dialect name
+	driverTypes = map[string]string{
+		"github.com/go-sql-driver/mysql.MySQLDriver": "mysql",
+		"github.com/lib/pq.Driver":                   "pq",
+		"github.com/jackc/pgx/stdlib.Driver":         "postgres",
+		"github.com/jackc/pgx/v4/stdlib.Driver":      "postgres",
+		"github.com/jackc/pgx/v5/stdlib.Driver":      "postgres",
+		"github.com/mattn/go-sqlite3.SQLiteDriver":   "sqlite",
+		"modernc.org/sqlite.Driver":                  "sqlite",
+	}
+)
+
+// driverProvider is interface which provides underlying driver like *sql.DB
+type driverProvider interface {
+	Driver() driver.Driver
+}
+
+// Register driver type for dialect detection on New().
+// d is instance of the driver like &mysql.MySQLDriver{}, and dialect is name of builtin or registered dialect.
+func RegisterDriverType(d driver.Driver, dialect string) {
+	if d == nil {
+		panic("gqb: RegisterDriverType driver is nil")
+	}
+	driverTypesMu.Lock()
+	defer driverTypesMu.Unlock()
+	driverTypes[driverTypeName(d)] = dialect
+}
+
+// Dialect option specifies dialect of the builder by name instead of detecting from executor.
+// This doesn't change dialect of other builders, unlike SetDriver().
+func Dialect(name string) Option {
+	return func(q *QueryBuilder) {
+		q.dialectFixed = true
+		c, err := lookupDialect(name)
+		if err != nil {
+			if q.err == nil {
+				q.err = err
+			}
+			return
+		}
+		q.compat = c
+	}
+}
+
+// Get type name of driver with package path like "github.com/lib/pq.Driver"
+func driverTypeName(d driver.Driver) string {
+	t := derefType(reflect.TypeOf(d))
+	return t.PkgPath() + "." + t.Name()
+}
+
+// Detect dialect from driver of executor like *sql.DB.
+// Returns nil for executor which doesn't expose driver like *sql.Tx, nil executor, or unknown driver, then builder uses default dialect.
+func detectDialect(db Executor) Compat {
+	p, ok := db.(driverProvider)
+	if !ok {
+		return nil
+	}
+	// Typed nil executor like (*sql.DB)(nil) would panic on Driver()
+	if v := reflect.ValueOf(p); v.Kind() == reflect.Ptr && v.IsNil() {
+		return nil
+	}
+	d := p.Driver()
+	if d == nil {
+		return nil
+	}
+	driverTypesMu.RLock()
+	name, ok := driverTypes[driverTypeName(d)]
+	driverTypesMu.RUnlock()
+	if !ok {
+		return nil
+	}
+	c, err := lookupDialect(name)
+	if err != nil {
+		return nil
+	}
+	return c
+}
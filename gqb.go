@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 
 	"database/sql"
 )
@@ -18,14 +19,104 @@ type QueryBuilder struct {
 	orders  []Order
 	selects []interface{}
 	joins   []Join
-	groupBy []string
+	groupBy []interface{}
+	err     error
+
+	// immutable indicates builder methods return new builder instead of modifying receiver
+	immutable bool
+
+	// softDelete is column name for soft delete, and trashed indicates how to treat soft deleted rows
+	softDelete string
+	trashed    trashedMode
+
+	// timestamp is configuration for automatic timestamps, and clock is time source for it
+	timestamp *Timestamp
+	clock     func() time.Time
+
+	// tenant is policy for multi-tenant row scoping
+	tenant *TenantPolicy
+
+	// allowFullTable and maxAffected are safety guards for UPDATE/DELETE query
+	allowFullTable bool
+	maxAffected    int64
+
+	// lockColumn is version column name for optimistic lock
+	lockColumn string
+
+	// atomic indicates multiple statement operations run in a single transaction
+	atomic bool
+
+	// loader is used for BulkLoad(), default loader is used if nil
+	loader Loader
+
+	// compat is dialect of the builder, and dialectFixed indicates dialect is specified by option, so detection is skipped
+	compat       Compat
+	dialectFixed bool
+
+	// from is source table when the builder is used as SELECT statement of other query like InsertFrom()
+	from interface{}
+
+	// lock is row locking clause of SELECT query
+	lock LockClause
+
+	// asOf is timestamp expression for historical read of CockroachDB
+	asOf interface{}
+
+	// final, sample and limitBy are ClickHouse specific SELECT modifiers
+	final   bool
+	sample  float64
+	limitBy *limitBy
+
+	// strict indicates identifiers are checked by StrictIdentifiers()
+	strict bool
 }
 
 // Create new Query QueryBuilder
-func New(db Executor) *QueryBuilder {
-	return &QueryBuilder{
-		db: db,
+// Dialect is detected from driver of db like *sql.DB unless Dialect option is specified.
+func New(db Executor, options ...Option) *QueryBuilder {
+	q := &QueryBuilder{
+		db:     db,
+		compat: defaultDialect(),
 	}
+	for _, o := range options {
+		o(q)
+	}
+	if !q.dialectFixed {
+		if c := detectDialect(db); c != nil {
+			q.compat = c
+		}
+	}
+	return q
+}
+
+// Clone() creates new builder which has copied stacks.
+// Modifying cloned builder doesn't affect to receiver, so this is useful for deriving queries from base query.
+func (q *QueryBuilder) Clone() *QueryBuilder {
+	c := *q
+	c.wheres = append([]ConditionBuilder{}, q.wheres...)
+	c.orders = append([]Order{}, q.orders...)
+	c.selects = append([]interface{}{}, q.selects...)
+	c.joins = append([]Join{}, q.joins...)
+	c.groupBy = append([]interface{}{}, q.groupBy...)
+	return &c
+}
+
+// Immutable() creates new builder with immutable mode.
+// In immutable mode, each chained method and query execution works on cloned builder,
+// so the builder can be reused as base query and shared between goroutines.
+func (q *QueryBuilder) Immutable() *QueryBuilder {
+	c := q.Clone()
+	c.immutable = true
+	return c
+}
+
+// derive() returns builder to be modified.
+// In immutable mode, returns cloned builder in order to keep receiver's state.
+func (q *QueryBuilder) derive() *QueryBuilder {
+	if q.immutable {
+		return q.Clone()
+	}
+	return q
 }
 
 // Reset() resets stacks
@@ -34,63 +125,179 @@ func (q *QueryBuilder) Reset() {
 	q.selects = []interface{}{}
 	q.joins = []Join{}
 	q.orders = []Order{}
-	q.groupBy = []string{}
+	q.groupBy = []interface{}{}
 	q.limit = 0
 	q.offset = 0
+	q.err = nil
+	q.trashed = withoutTrashed
+	q.allowFullTable = false
+	q.maxAffected = 0
+	q.atomic = false
+	q.from = nil
+	q.asOf = nil
+	q.lock = ""
+	q.final = false
+	q.sample = 0
+	q.limitBy = nil
+}
+
+// Check the builder has stacked state of query like conditions, joins, orders and limit
+func (q *QueryBuilder) hasQueryState() bool {
+	return len(q.wheres) > 0 || len(q.selects) > 0 || len(q.joins) > 0 || len(q.orders) > 0 || len(q.groupBy) > 0 ||
+		q.limit != 0 || q.offset != 0 || q.from != nil || q.asOf != nil || q.lock != "" ||
+		q.final || q.sample != 0 || q.limitBy != nil
+}
+
+// Set source table for using the builder as SELECT statement of InsertFrom()
+func (q *QueryBuilder) From(table interface{}) *QueryBuilder {
+	q = q.derive()
+	q.from = table
+	return q
+}
+
+// Read historical data with "AS OF SYSTEM TIME" on CockroachDB.
+// at accepts interval string like "-10s", time.Time, or Raw expression like Raw("follower_read_timestamp()").
+func (q *QueryBuilder) AsOfSystemTime(at interface{}) *QueryBuilder {
+	q = q.derive()
+	q.asOf = at
+	return q
+}
+
+// Create "AS OF SYSTEM TIME" clause
+func (q *QueryBuilder) buildAsOf() (string, error) {
+	if q.asOf == nil {
+		return "", nil
+	} else if !q.compat.Capabilities().Supports(FeatureAsOfSystemTime) {
+		return "", fmt.Errorf("AS OF SYSTEM TIME is not supported on this driver")
+	}
+	switch t := q.asOf.(type) {
+	case Raw:
+		return " AS OF SYSTEM TIME " + t.String(), nil
+	case time.Time:
+		return " AS OF SYSTEM TIME '" + t.UTC().Format(datetimeFormat+".999999") + "'", nil
+	case string:
+		return " AS OF SYSTEM TIME '" + strings.Replace(t, "'", "''", -1) + "'", nil
+	}
+	return "", fmt.Errorf("AS OF SYSTEM TIME accepts string, time.Time or Raw")
 }
 
 // Add SELECT fields
 func (q *QueryBuilder) Select(fields ...interface{}) *QueryBuilder {
+	q = q.derive()
+	for _, f := range fields {
+		if v, ok := f.(validator); ok && q.err == nil {
+			q.err = v.validate()
+		}
+	}
 	q.selects = append(q.selects, fields...)
 	return q
 }
 
 // Add SELECT COUNT fields
-func (q *QueryBuilder) SelectCount(field string) *QueryBuilder {
-	q.selects = append(q.selects, Raw("COUNT("+quote(field)+")"))
-	return q
+func (q *QueryBuilder) SelectCount(field interface{}) *QueryBuilder {
+	return q.selectAggregate("COUNT", field)
 }
 
 // Add SELECT MAX fields
-func (q *QueryBuilder) SelectMax(field string) *QueryBuilder {
-	q.selects = append(q.selects, Raw("MAX("+quote(field)+")"))
-	return q
+func (q *QueryBuilder) SelectMax(field interface{}) *QueryBuilder {
+	return q.selectAggregate("MAX", field)
 }
 
 // Add SELECT MIN fields
-func (q *QueryBuilder) SelectMin(field string) *QueryBuilder {
-	q.selects = append(q.selects, Raw("MIN("+quote(field)+")"))
-	return q
+func (q *QueryBuilder) SelectMin(field interface{}) *QueryBuilder {
+	return q.selectAggregate("MIN", field)
 }
 
 // Add SELECT AVG fields
-func (q *QueryBuilder) SelectAvg(field string) *QueryBuilder {
-	q.selects = append(q.selects, Raw("AVG("+quote(field)+")"))
+func (q *QueryBuilder) SelectAvg(field interface{}) *QueryBuilder {
+	return q.selectAggregate("AVG", field)
+}
+
+// Add aggregate function field like COUNT(field), field is string, Ident or Raw
+func (q *QueryBuilder) selectAggregate(function string, field interface{}) *QueryBuilder {
+	q = q.derive()
+	if err := checkIdentType(field); err != nil {
+		if q.err == nil {
+			q.err = err
+		}
+		return q
+	} else if err := q.checkIdents(field); err != nil && q.err == nil {
+		q.err = err
+	}
+	q.selects = append(q.selects, Raw(function+"("+quote(q.compat, field)+")"))
 	return q
 }
 
 // Set LIMIT field
 func (q *QueryBuilder) Limit(limit int64) *QueryBuilder {
+	q = q.derive()
 	q.limit = limit
 	return q
 }
 
 // Set OFFSET field
 func (q *QueryBuilder) Offset(offset int64) *QueryBuilder {
+	q = q.derive()
 	q.offset = offset
 	return q
 }
 
-// Add JOIN table with condition
-func (q *QueryBuilder) Join(table, from, to string, c Comparison) *QueryBuilder {
-	q.joins = append(q.joins, Join{
+// Add JOIN table with condition, table can be aliased by Alias()
+func (q *QueryBuilder) Join(table, from, to interface{}, c Comparison) *QueryBuilder {
+	q = q.derive()
+	j := Join{
 		on: condition{
 			comparison: c,
 			field:      from,
 			value:      to,
 		},
 		table: table,
-	})
+	}
+	if err := j.validate(); err != nil && q.err == nil {
+		q.err = err
+	}
+	q.joins = append(q.joins, j)
+	return q
+}
+
+// Add FULL OUTER JOIN table, it returns error on execution if driver doesn't support it
+func (q *QueryBuilder) FullJoin(table, from, to interface{}, c Comparison) *QueryBuilder {
+	q = q.derive()
+	j := Join{
+		on: condition{
+			comparison: c,
+			field:      from,
+			value:      to,
+		},
+		table: table,
+		full:  true,
+	}
+	if !q.compat.Capabilities().FullJoin && q.err == nil {
+		q.err = fmt.Errorf("FULL OUTER JOIN is not supported on this driver")
+	} else if err := j.validate(); err != nil && q.err == nil {
+		q.err = err
+	}
+	q.joins = append(q.joins, j)
+	return q
+}
+
+// Lock selected rows with locking clause like LockForUpdate, it returns error on execution if driver doesn't support it
+func (q *QueryBuilder) Lock(clause LockClause) *QueryBuilder {
+	q = q.derive()
+	if !q.compat.Capabilities().supportsLock(clause) && q.err == nil {
+		q.err = fmt.Errorf("%s is not supported on this driver", clause)
+	}
+	q.lock = clause
+	return q
+}
+
+// Apply scopes in order.
+// Each scope receives the builder and should return the builder which is chained from it.
+func (q *QueryBuilder) Scopes(scopes ...Scope) *QueryBuilder {
+	q = q.derive()
+	for _, s := range scopes {
+		q = s(q)
+	}
 	return q
 }
 
@@ -100,8 +307,7 @@ func (q *QueryBuilder) Join(table, from, to string, c Comparison) *QueryBuilder
 func (q *QueryBuilder) WhereGroup(generator func(g *WhereGroup)) *QueryBuilder {
 	cg := newWhereGroup(And)
 	generator(cg)
-	q.wheres = append(q.wheres, cg)
-	return q
+	return q.AddWhere(cg)
 }
 
 // Add WHERE condition group with OR.
@@ -110,34 +316,60 @@ func (q *QueryBuilder) WhereGroup(generator func(g *WhereGroup)) *QueryBuilder {
 func (q *QueryBuilder) OrWhereGroup(generator func(g *WhereGroup)) *QueryBuilder {
 	cg := newWhereGroup(Or)
 	generator(cg)
-	q.wheres = append(q.wheres, cg)
-	return q
+	return q.AddWhere(cg)
 }
 
 // Add condition
 func (q *QueryBuilder) AddWhere(c ConditionBuilder) *QueryBuilder {
+	q = q.derive()
+	if v, ok := c.(validator); ok && q.err == nil {
+		q.err = v.validate()
+	}
 	q.wheres = append(q.wheres, c)
 	return q
 }
 
-// Add user specific raw condition with AND combination
-func (q *QueryBuilder) WhereRaw(raw string) *QueryBuilder {
+// Add condition which always restricts results with AND combination
+func (q *QueryBuilder) restrict(c ConditionBuilder) {
+	q.wheres = restrictConditions(q.wheres, c)
+}
+
+// Get WHERE conditions with implicit conditions like soft delete and tenant.
+// The implicit conditions aren't stacked on builder in order to build multiple queries from the same state.
+func (q *QueryBuilder) conditions(ctx context.Context, table interface{}) ([]ConditionBuilder, error) {
+	wheres := q.wheres
+	if c := q.softDeleteCondition(table); c != nil {
+		wheres = restrictConditions(wheres, c)
+	}
+	c, err := q.tenantCondition(ctx, table)
+	if err != nil {
+		return nil, err
+	} else if c != nil {
+		wheres = restrictConditions(wheres, c)
+	}
+	return wheres, nil
+}
+
+// Add user specific raw condition with AND combination.
+// Bind parameters are applied to "?" or "$n" placeholders, or Params is applied to ":name" or "@name" placeholders in raw condition.
+func (q *QueryBuilder) WhereRaw(raw string, binds ...interface{}) *QueryBuilder {
 	return q.AddWhere(rawCondition{
-		rawClause: raw,
-		combine:   And,
+		expr:    newRawExpr(raw, binds),
+		combine: And,
 	})
 }
 
-// Add user specific raw condition with OR combination
-func (q *QueryBuilder) OrWhereRaw(raw string) *QueryBuilder {
+// Add user specific raw condition with OR combination.
+// Bind parameters are applied to "?" or "$n" placeholders, or Params is applied to ":name" or "@name" placeholders in raw condition.
+func (q *QueryBuilder) OrWhereRaw(raw string, binds ...interface{}) *QueryBuilder {
 	return q.AddWhere(rawCondition{
-		rawClause: raw,
-		combine:   Or,
+		expr:    newRawExpr(raw, binds),
+		combine: Or,
 	})
 }
 
 // Add condition with AND combination
-func (q *QueryBuilder) Where(field string, value interface{}, comparison Comparison) *QueryBuilder {
+func (q *QueryBuilder) Where(field interface{}, value interface{}, comparison Comparison) *QueryBuilder {
 	return q.AddWhere(condition{
 		comparison: comparison,
 		field:      field,
@@ -147,7 +379,7 @@ func (q *QueryBuilder) Where(field string, value interface{}, comparison Compari
 }
 
 // Add condition with OR combination
-func (q *QueryBuilder) OrWhere(field string, value interface{}, comparison Comparison) *QueryBuilder {
+func (q *QueryBuilder) OrWhere(field interface{}, value interface{}, comparison Comparison) *QueryBuilder {
 	return q.AddWhere(condition{
 		comparison: comparison,
 		field:      field,
@@ -157,7 +389,7 @@ func (q *QueryBuilder) OrWhere(field string, value interface{}, comparison Compa
 }
 
 // Add IN condition with AND combination
-func (q *QueryBuilder) WhereIn(field string, values ...interface{}) *QueryBuilder {
+func (q *QueryBuilder) WhereIn(field interface{}, values ...interface{}) *QueryBuilder {
 	return q.AddWhere(condition{
 		comparison: In,
 		field:      field,
@@ -167,7 +399,7 @@ func (q *QueryBuilder) WhereIn(field string, values ...interface{}) *QueryBuilde
 }
 
 // Add IN condition with OR combination
-func (q *QueryBuilder) OrWhereIn(field string, values ...interface{}) *QueryBuilder {
+func (q *QueryBuilder) OrWhereIn(field interface{}, values ...interface{}) *QueryBuilder {
 	return q.AddWhere(condition{
 		comparison: In,
 		field:      field,
@@ -177,7 +409,7 @@ func (q *QueryBuilder) OrWhereIn(field string, values ...interface{}) *QueryBuil
 }
 
 // Add NOT IN condition with AND combination
-func (q *QueryBuilder) WhereNotIn(field string, values ...interface{}) *QueryBuilder {
+func (q *QueryBuilder) WhereNotIn(field interface{}, values ...interface{}) *QueryBuilder {
 	return q.AddWhere(condition{
 		comparison: NotIn,
 		field:      field,
@@ -187,7 +419,7 @@ func (q *QueryBuilder) WhereNotIn(field string, values ...interface{}) *QueryBui
 }
 
 // Add NOT IN condition with OR combination
-func (q *QueryBuilder) OrWhereNotIn(field string, values ...interface{}) *QueryBuilder {
+func (q *QueryBuilder) OrWhereNotIn(field interface{}, values ...interface{}) *QueryBuilder {
 	return q.AddWhere(condition{
 		comparison: NotIn,
 		field:      field,
@@ -197,7 +429,7 @@ func (q *QueryBuilder) OrWhereNotIn(field string, values ...interface{}) *QueryB
 }
 
 // Add LIKE condition with AND combination
-func (q *QueryBuilder) Like(field string, value interface{}) *QueryBuilder {
+func (q *QueryBuilder) Like(field interface{}, value interface{}) *QueryBuilder {
 	return q.AddWhere(condition{
 		comparison: Like,
 		field:      field,
@@ -207,7 +439,7 @@ func (q *QueryBuilder) Like(field string, value interface{}) *QueryBuilder {
 }
 
 // Add LIKE condition with OR combination
-func (q *QueryBuilder) OrLike(field string, value interface{}) *QueryBuilder {
+func (q *QueryBuilder) OrLike(field interface{}, value interface{}) *QueryBuilder {
 	return q.AddWhere(condition{
 		comparison: Like,
 		field:      field,
@@ -217,7 +449,7 @@ func (q *QueryBuilder) OrLike(field string, value interface{}) *QueryBuilder {
 }
 
 // Add NOT LIKE condition with AND combination
-func (q *QueryBuilder) NotLike(field string, value interface{}) *QueryBuilder {
+func (q *QueryBuilder) NotLike(field interface{}, value interface{}) *QueryBuilder {
 	return q.AddWhere(condition{
 		comparison: NotLike,
 		field:      field,
@@ -227,7 +459,7 @@ func (q *QueryBuilder) NotLike(field string, value interface{}) *QueryBuilder {
 }
 
 // Add NOT LIKE condition with OR combination
-func (q *QueryBuilder) OrNotLike(field string, value interface{}) *QueryBuilder {
+func (q *QueryBuilder) OrNotLike(field interface{}, value interface{}) *QueryBuilder {
 	return q.AddWhere(condition{
 		comparison: NotLike,
 		field:      field,
@@ -237,33 +469,75 @@ func (q *QueryBuilder) OrNotLike(field string, value interface{}) *QueryBuilder
 }
 
 // Add GROUP BY clause
-func (q *QueryBuilder) GroupBy(fields ...string) *QueryBuilder {
+func (q *QueryBuilder) GroupBy(fields ...interface{}) *QueryBuilder {
+	q = q.derive()
+	for _, f := range fields {
+		if err := checkIdentType(f); err != nil && q.err == nil {
+			q.err = err
+		}
+	}
 	q.groupBy = append(q.groupBy, fields...)
 	return q
 }
 
 // Add ORDER BY cluase
-func (q *QueryBuilder) OrderBy(field string, sort SortMode) *QueryBuilder {
-	q.orders = append(q.orders, Order{
+func (q *QueryBuilder) OrderBy(field interface{}, sort SortMode) *QueryBuilder {
+	q = q.derive()
+	o := Order{
 		field: field,
 		sort:  sort,
-	})
+	}
+	if err := o.validate(); err != nil && q.err == nil {
+		q.err = err
+	}
+	q.orders = append(q.orders, o)
 	return q
 }
 
 // Format FROM table
 func (q *QueryBuilder) formatTable(table interface{}) (string, error) {
-	if v, ok := table.(alias); ok {
-		return v.String(), nil
-	} else if v, ok := table.(string); ok {
+	if err := q.checkIdents(q.identifiers(table)...); err != nil {
+		return "", err
+	}
+	switch v := table.(type) {
+	case alias:
+		if err := v.validate(); err != nil {
+			return "", err
+		}
+		return v.build(q.compat), nil
+	case string:
 		if v == "" {
 			return "", fmt.Errorf("Table name must not be empty")
 		}
-		return quote(v), nil
+		return quote(q.compat, schemaTable(v)), nil
+	case Ident:
+		if len(v) == 0 {
+			return "", fmt.Errorf("Table name must not be empty")
+		}
+		return quote(q.compat, schemaTable(v)), nil
 	}
 	return "", fmt.Errorf("Invalid table specified")
 }
 
+// Qualify column with table name in order to avoid ambiguous column on JOIN
+func (q *QueryBuilder) qualify(table interface{}, column string) interface{} {
+	if len(q.joins) == 0 {
+		return column
+	}
+	switch v := table.(type) {
+	case alias:
+		if strings.Contains(v.to, ".") {
+			return Raw(quote(q.compat, aliasIdent(v.to)) + "." + quote(q.compat, column))
+		}
+		return v.to + "." + column
+	case string:
+		return v + "." + column
+	case Ident:
+		return append(append(Ident{}, v...), column)
+	}
+	return column
+}
+
 // Execute query and get first result
 func (q *QueryBuilder) GetOne(table interface{}) (*Result, error) {
 	return q.GetOneContext(context.Background(), table)
@@ -271,6 +545,7 @@ func (q *QueryBuilder) GetOne(table interface{}) (*Result, error) {
 
 // Execute query and get first result with context
 func (q *QueryBuilder) GetOneContext(ctx context.Context, table interface{}) (*Result, error) {
+	q = q.derive()
 	q.limit = 1
 	r, err := q.GetContext(ctx, table)
 	if err != nil {
@@ -288,34 +563,130 @@ func (q *QueryBuilder) Get(table interface{}) (Results, error) {
 
 // Execute query and get results with context
 func (q *QueryBuilder) GetContext(ctx context.Context, table interface{}) (Results, error) {
-	mainTable, err := q.formatTable(table)
+	q = q.derive()
+	query, binds, err := q.buildSelectQuery(ctx, table)
 	if err != nil {
 		return nil, err
 	}
-	where, binds := buildWhere(q.wheres, []interface{}{})
+
+	defer q.Reset()
+	rows, err := q.db.QueryContext(ctx, query, binds...)
+	if err != nil {
+		return nil, err
+	}
+	// gqb close rows pointer automatically so user don't need to care about it.
+	// but allocate some more memories to make results
+	defer rows.Close()
+	return q.scan(rows)
+}
+
+// Create LIMIT and OFFSET phrase of the driver.
+// "LIMIT -1" is written for offset without limit if driver requires LIMIT clause for OFFSET clause, negative value means no limit.
+func (q *QueryBuilder) buildLimitOffset() (string, error) {
+	limit, err := q.compat.LimitOffset(q.limit, q.offset, len(q.orders) > 0)
+	if err != nil {
+		return "", err
+	}
+	if q.compat.Capabilities().OffsetRequiresLimit && q.limit == 0 && q.offset > 0 {
+		limit = " LIMIT -1" + limit
+	}
+	return limit, nil
+}
+
+// Build SELECT query from stacked state
+func (q *QueryBuilder) buildSelectQuery(ctx context.Context, table interface{}) (string, []interface{}, error) {
+	if q.err != nil {
+		return "", nil, q.err
+	}
+	mainTable, err := q.formatTable(table)
+	if err != nil {
+		return "", nil, err
+	}
+	wheres, err := q.conditions(ctx, table)
+	if err != nil {
+		return "", nil, err
+	}
+	limit, err := q.buildLimitOffset()
+	if err != nil {
+		return "", nil, err
+	}
+	modifiers, err := q.buildTableModifiers()
+	if err != nil {
+		return "", nil, err
+	}
+	limitBy, err := q.buildLimitBy()
+	if err != nil {
+		return "", nil, err
+	}
+	asOf, err := q.buildAsOf()
+	if err != nil {
+		return "", nil, err
+	}
+	fields, binds := buildSelectFields(q.compat, q.selects, []interface{}{})
+	where, binds := buildWhere(q.compat, wheres, binds)
 	query := strings.TrimSpace(fmt.Sprintf(
-		"SELECT %s FROM %s%s%s%s%s%s%s",
-		buildSelectFields(q.selects),
+		"SELECT %s FROM %s%s%s%s%s%s%s%s%s",
+		fields,
 		mainTable,
-		buildJoin(q.joins, mainTable),
+		modifiers,
+		buildJoin(q.compat, q.joins, targetTable(table)),
+		asOf,
 		where,
-		buildGroupBy(q.groupBy),
-		buildOrderBy(q.orders),
-		buildLimit(q.limit),
-		buildOffset(q.offset),
+		buildGroupBy(q.compat, q.groupBy),
+		buildOrderBy(q.compat, q.orders),
+		limitBy,
+		limit,
 	))
+	if w, ok := q.compat.(limitWrapper); ok {
+		query = w.WrapLimitOffset(query, q.limit, q.offset)
+	}
+	if q.lock != "" {
+		// Oracle rejects locking clause with FETCH FIRST and ROWNUM subquery
+		if q.compat.Capabilities().LockWithoutLimit && (q.limit > 0 || q.offset > 0) {
+			return "", nil, fmt.Errorf("Lock() could not be used with Limit() or Offset() on this driver")
+		}
+		query += " " + string(q.lock)
+	}
+	return query, binds, nil
+}
 
-	defer q.Reset()
+// Execute raw query and get results.
+// Placeholders of "?" or "$n", or ":name" or "@name" with Params are rewritten to driver's placeholder.
+func (q *QueryBuilder) Query(ctx context.Context, query string, binds ...interface{}) (Results, error) {
+	if q.tenant != nil {
+		return nil, ErrTenantRawQuery
+	}
+	query, binds, err := buildRawQuery(q.compat, query, binds)
+	if err != nil {
+		return nil, err
+	}
 	rows, err := q.db.QueryContext(ctx, query, binds...)
 	if err != nil {
 		return nil, err
 	}
-	// gqb close rows pointer automatically so user don't need to care about it.
-	// but allocate some more memories to make results
 	defer rows.Close()
 	return q.scan(rows)
 }
 
+// Execute raw query with named parameters and get results.
+// Named placeholders of ":name" or "@name" are rewritten to driver's placeholder.
+func (q *QueryBuilder) RawQuery(ctx context.Context, query string, params Params) (Results, error) {
+	return q.Query(ctx, query, params)
+}
+
+// Execute raw query which doesn't return rows like INSERT, UPDATE, DELETE.
+// Placeholders of "?" or "$n", or ":name" or "@name" with Params are rewritten to driver's placeholder.
+func (q *QueryBuilder) Exec(ctx context.Context, query string, binds ...interface{}) (sql.Result, error) {
+	if q.tenant != nil {
+		return nil, ErrTenantRawQuery
+	}
+	query, binds, err := buildRawQuery(q.compat, query, binds)
+	if err != nil {
+		return nil, err
+	}
+	return q.db.ExecContext(ctx, query, binds...)
+}
+
 // Scan rows to map to result
 func (q *QueryBuilder) scan(rows *sql.Rows) (Results, error) {
 	columns, err := rows.ColumnTypes()
@@ -366,34 +737,65 @@ func (q *QueryBuilder) Update(table interface{}, data Data) (sql.Result, error)
 	return q.UpdateContext(context.Background(), table, data)
 }
 
-// Execute UPDATE query with context
+// Execute UPDATE query with context.
+// If optimistic lock is enabled, Data must have current version value and ErrStaleObject is returned when no rows are updated.
 func (q *QueryBuilder) UpdateContext(ctx context.Context, table interface{}, data Data) (sql.Result, error) {
+	return q.derive().updateContext(ctx, table, data, true)
+}
+
+// Execute UPDATE query actually.
+// lock indicates whether to apply optimistic lock, it is disabled on soft delete and restore.
+func (q *QueryBuilder) updateContext(ctx context.Context, table interface{}, data Data, lock bool) (sql.Result, error) {
 	if data == nil {
 		return nil, fmt.Errorf("update data must be non-nil")
+	} else if q.err != nil {
+		return nil, q.err
+	} else if err := q.checkMutation(); err != nil {
+		return nil, err
+	}
+	data = q.stampData(data, false)
+	if err := q.checkIdentifiers(data.Keys()...); err != nil {
+		return nil, err
 	}
 	mainTable, err := q.formatTable(table)
 	if err != nil {
 		return nil, err
 	}
-	var where, updates string
+	wheres, err := q.conditions(ctx, table)
+	if err != nil {
+		return nil, err
+	} else if err := q.checkTenantData(ctx, table, data); err != nil {
+		return nil, err
+	}
+	var version ConditionBuilder
+	if lock {
+		if data, version, err = q.lockData(data); err != nil {
+			return nil, err
+		} else if version != nil && !q.compat.Capabilities().AffectedRows {
+			return nil, fmt.Errorf("OptimisticLock() is not supported because driver doesn't report affected rows")
+		} else if version != nil {
+			wheres = restrictConditions(wheres, version)
+		}
+	}
+	var updates string
 	binds := []interface{}{}
 
 	for _, k := range data.Keys() {
-		updates += quote(k) + " = " + driverCompat.PlaceHolder(len(binds)+1) + ", "
-		binds = bind(binds, data[k])
+		var value string
+		value, binds = bindValue(q.compat, binds, data[k])
+		updates += quote(q.compat, k) + " = " + value + ", "
+	}
+	query, binds, err := q.buildUpdateQuery(table, mainTable, strings.TrimRight(updates, ", "), wheres, binds)
+	if err != nil {
+		return nil, err
 	}
-	where, binds = buildWhere(q.wheres, binds)
-
-	query := strings.TrimSpace(fmt.Sprintf(
-		"UPDATE %s SET %s%s%s",
-		mainTable,
-		strings.TrimRight(updates, ", "),
-		where,
-		buildLimit(q.limit),
-	))
 
 	defer q.Reset()
-	return q.db.ExecContext(ctx, query, binds...)
+	result, err := q.execMutation(ctx, query, binds)
+	if err != nil || version == nil {
+		return result, err
+	}
+	return result, checkStale(result)
 }
 
 // Execute INSERT query
@@ -403,30 +805,133 @@ func (q *QueryBuilder) Insert(table interface{}, data Data) (sql.Result, error)
 
 // Execute INSERT query with context
 func (q *QueryBuilder) InsertContext(ctx context.Context, table interface{}, data Data) (sql.Result, error) {
+	q = q.derive()
+	query, binds, err := q.buildInsertQuery(ctx, table, data, "", "")
+	if err != nil {
+		return nil, err
+	}
+	defer q.Reset()
+	return q.db.ExecContext(ctx, query, binds...)
+}
+
+// Execute INSERT query and get inserted row.
+// Returning columns are all columns if not specified.
+// PostgreSQL and SQLite use "RETURNING", and SQL Server uses "OUTPUT INSERTED".
+func (q *QueryBuilder) InsertReturning(ctx context.Context, table interface{}, data Data, columns ...string) (Results, error) {
+	q = q.derive()
+	if err := q.checkIdentifiers(columns...); err != nil {
+		return nil, err
+	}
+	output, suffix, err := buildReturning(q.compat, columns)
+	if err != nil {
+		return nil, err
+	}
+	query, binds, err := q.buildInsertQuery(ctx, table, data, output, suffix)
+	if err != nil {
+		return nil, err
+	}
+	defer q.Reset()
+	rows, err := q.db.QueryContext(ctx, query, binds...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return q.scan(rows)
+}
+
+// Execute INSERT query and scan inserted values into destinations of into.
+// into is map of column name and pointer of destination like gqb.Params{"id": &id}.
+// Oracle uses "RETURNING ... INTO" with out bind parameters, and other drivers use the same phrase as InsertReturning().
+func (q *QueryBuilder) InsertReturningInto(ctx context.Context, table interface{}, data Data, into Params) error {
+	q = q.derive()
+	if len(into) == 0 {
+		return fmt.Errorf("returning destinations must not be empty")
+	}
+	columns := Data(into).Keys()
+	if err := q.checkIdentifiers(columns...); err != nil {
+		return err
+	}
+	dest := []interface{}{}
+	for _, c := range columns {
+		dest = append(dest, into[c])
+	}
+
+	defer q.Reset()
+	if q.compat.Capabilities().Returning == ReturningInto {
+		query, binds, err := q.buildInsertQuery(ctx, table, data, "", "")
+		if err != nil {
+			return err
+		}
+		fields := []string{}
+		values := []string{}
+		for i, c := range columns {
+			fields = append(fields, quote(q.compat, c))
+			values = append(values, q.compat.PlaceHolder(len(binds)+1))
+			binds = append(binds, sql.Out{Dest: dest[i]})
+		}
+		query += fmt.Sprintf(" RETURNING %s INTO %s", strings.Join(fields, ", "), strings.Join(values, ", "))
+		_, err = q.db.ExecContext(ctx, query, binds...)
+		return err
+	}
+
+	output, suffix, err := buildReturning(q.compat, columns)
+	if err != nil {
+		return err
+	}
+	query, binds, err := q.buildInsertQuery(ctx, table, data, output, suffix)
+	if err != nil {
+		return err
+	}
+	rows, err := q.db.QueryContext(ctx, query, binds...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		return sql.ErrNoRows
+	}
+	return rows.Scan(dest...)
+}
+
+// Build INSERT query with timestamps and tenant policy.
+// output is placed before VALUES and suffix is placed at the end of query for returning inserted row.
+func (q *QueryBuilder) buildInsertQuery(ctx context.Context, table interface{}, data Data, output, suffix string) (string, []interface{}, error) {
 	if data == nil {
-		return nil, fmt.Errorf("insert data must be non-nil")
+		return "", nil, fmt.Errorf("insert data must be non-nil")
+	}
+	data = q.stampData(data, true)
+	if err := q.checkIdentifiers(data.Keys()...); err != nil {
+		return "", nil, err
 	}
 	mainTable, err := q.formatTable(table)
 	if err != nil {
-		return nil, err
+		return "", nil, err
+	}
+	if data, err = q.tenantData(ctx, table, data); err != nil {
+		return "", nil, err
 	}
 
 	var fields, values string
 	binds := []interface{}{}
 
 	for _, k := range data.Keys() {
-		fields += quote(k) + ", "
-		values += driverCompat.PlaceHolder(len(binds)+1) + ", "
-		binds = bind(binds, data[k])
+		var value string
+		value, binds = bindValue(q.compat, binds, data[k])
+		fields += quote(q.compat, k) + ", "
+		values += value + ", "
 	}
 	query := fmt.Sprintf(
-		"INSERT INTO %s (%s) VALUES (%s)",
+		"INSERT INTO %s (%s)%s VALUES (%s)%s",
 		mainTable,
 		strings.TrimRight(fields, ", "),
+		output,
 		strings.TrimRight(values, ", "),
+		suffix,
 	)
-	defer q.Reset()
-	return q.db.ExecContext(ctx, query, binds...)
+	return query, binds, nil
 }
 
 // Execute bulk INSERT query
@@ -434,36 +939,121 @@ func (q *QueryBuilder) BulkInsert(table interface{}, data []Data) (sql.Result, e
 	return q.BulkInsertContext(context.Background(), table, data)
 }
 
-// Execute bulk INSERT query with context
+// Execute bulk INSERT query with context.
+// All rows must have the same keys, and rows are split into multiple queries
+// when bind parameters exceed the driver's limit. Call Atomic() to run them in a single transaction.
 func (q *QueryBuilder) BulkInsertContext(ctx context.Context, table interface{}, data []Data) (sql.Result, error) {
+	q = q.derive()
 	if data == nil {
 		return nil, fmt.Errorf("insert data must be non-nil")
+	} else if len(data) == 0 {
+		return nil, fmt.Errorf("insert data must not be empty")
 	}
 	mainTable, err := q.formatTable(table)
 	if err != nil {
 		return nil, err
 	}
 
+	rows := make([]Data, len(data))
+	for i, d := range data {
+		d = q.stampData(d, true)
+		if d, err = q.tenantData(ctx, table, d); err != nil {
+			return nil, err
+		}
+		rows[i] = d
+	}
+	keys := rows[0].Keys()
+	for i, d := range rows {
+		if !sameKeys(keys, d.Keys()) {
+			return nil, fmt.Errorf("insert data at %d has different keys from the first data", i)
+		}
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("insert data must have at least one column")
+	} else if err := q.checkIdentifiers(keys...); err != nil {
+		return nil, err
+	}
+
 	var fields string
-	valueGroup := []string{}
-	binds := []interface{}{}
+	for _, k := range keys {
+		fields += quote(q.compat, k) + ", "
+	}
+	size := insertChunkSize(q.compat, len(keys))
+	if size == 0 {
+		return nil, fmt.Errorf("insert data has too many columns")
+	}
 
-	for i, d := range data {
-		var values string
-		for _, k := range d.Keys() {
-			if i == 0 {
-				fields += quote(k) + ", "
+	queries := []batchQuery{}
+	for start := 0; start < len(rows); start += size {
+		end := start + size
+		if end > len(rows) {
+			end = len(rows)
+		}
+		valueGroup := []string{}
+		binds := []interface{}{}
+		for _, d := range rows[start:end] {
+			var values string
+			for _, k := range keys {
+				var value string
+				value, binds = bindValue(q.compat, binds, d[k])
+				values += value + ", "
 			}
-			values += driverCompat.PlaceHolder(len(binds)+1) + ", "
-			binds = bind(binds, d[k])
+			valueGroup = append(valueGroup, "("+strings.TrimRight(values, ", ")+")")
 		}
-		valueGroup = append(valueGroup, "("+strings.TrimRight(values, ", ")+")")
+		queries = append(queries, batchQuery{
+			query: buildBulkInsert(q.compat, mainTable, strings.TrimRight(fields, ", "), valueGroup),
+			binds: binds,
+		})
+	}
+	defer q.Reset()
+	return q.execBatch(ctx, queries)
+}
+
+// Execute INSERT ... SELECT query which inserts rows selected by other builder.
+// The source builder must have source table by From(), and its fields should match to columns.
+// Conditions, joins, orders and limit must be stacked on the source builder, the builder which executes query returns error if it has them.
+// Note that selected rows are inserted as they are, so timestamps are not applied for inserting columns.
+// Under tenant policy, source rows are scoped by the policy and columns must contain tenant column.
+func (q *QueryBuilder) InsertFrom(ctx context.Context, table interface{}, columns []string, src *QueryBuilder) (sql.Result, error) {
+	q = q.derive()
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("columns must not be empty")
+	} else if src == nil {
+		return nil, fmt.Errorf("select builder must be non-nil")
+	} else if src.from == nil {
+		return nil, fmt.Errorf("select builder must have source table by From()")
+	} else if q.err != nil {
+		return nil, q.err
+	} else if q.hasQueryState() {
+		return nil, fmt.Errorf("InsertFrom() doesn't accept stacked query state of the builder, stack it on select builder")
+	} else if err := q.checkIdentifiers(columns...); err != nil {
+		return nil, err
+	}
+	mainTable, err := q.formatTable(table)
+	if err != nil {
+		return nil, err
+	}
+	if src, err = q.tenantSource(ctx, table, columns, src); err != nil {
+		return nil, err
+	}
+	// SELECT phrase is built on the dialect of the builder which executes query
+	src = src.Clone()
+	src.compat = q.compat
+	// INSERT phrase doesn't have any bind parameters, so placeholders of SELECT phrase start from the first index
+	selectQuery, binds, err := src.buildSelectQuery(ctx, src.from)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := []string{}
+	for _, c := range columns {
+		fields = append(fields, quote(q.compat, c))
 	}
 	query := fmt.Sprintf(
-		"INSERT INTO %s (%s) VALUES %s",
+		"INSERT INTO %s (%s) %s",
 		mainTable,
-		strings.TrimRight(fields, ", "),
-		strings.Join(valueGroup, ", "),
+		strings.Join(fields, ", "),
+		selectQuery,
 	)
 	defer q.Reset()
 	return q.db.ExecContext(ctx, query, binds...)
@@ -474,18 +1064,59 @@ func (q *QueryBuilder) Delete(table interface{}) (sql.Result, error) {
 	return q.DeleteContext(context.Background(), table)
 }
 
-// Execute DELETE query with context
+// Execute DELETE query with context.
+// If soft delete is enabled, execute UPDATE query which sets current time to soft delete column instead.
 func (q *QueryBuilder) DeleteContext(ctx context.Context, table interface{}) (sql.Result, error) {
+	q = q.derive()
+	if q.softDelete != "" {
+		return q.updateContext(ctx, table, Data{
+			q.softDelete: q.now(),
+		}, false)
+	}
+	return q.deleteContext(ctx, table)
+}
+
+// Execute DELETE query actually
+func (q *QueryBuilder) deleteContext(ctx context.Context, table interface{}) (sql.Result, error) {
+	if q.err != nil {
+		return nil, q.err
+	} else if err := q.checkMutation(); err != nil {
+		return nil, err
+	}
 	mainTable, err := q.formatTable(table)
 	if err != nil {
 		return nil, err
 	}
-	where, binds := buildWhere(q.wheres, []interface{}{})
-	query := strings.TrimSpace(fmt.Sprintf(
-		"DELETE FROM %s%s",
-		mainTable,
-		where,
-	))
+	wheres, err := q.conditions(ctx, table)
+	if err != nil {
+		return nil, err
+	}
+	query, binds, err := q.buildDeleteQuery(table, mainTable, wheres)
+	if err != nil {
+		return nil, err
+	}
 	defer q.Reset()
-	return q.db.ExecContext(ctx, query, binds...)
+	return q.execMutation(ctx, query, binds)
+}
+
+// Create bulk INSERT query from VALUES groups.
+// Oracle doesn't accept multi-row VALUES, so each row is inserted by "INTO" clause of "INSERT ALL".
+func buildBulkInsert(compat Compat, mainTable, fields string, valueGroup []string) string {
+	if compat.Capabilities().BulkInsert != BulkInsertAll {
+		return fmt.Sprintf("INSERT INTO %s (%s) VALUES %s", mainTable, fields, strings.Join(valueGroup, ", "))
+	}
+	query := "INSERT ALL"
+	for _, v := range valueGroup {
+		query += fmt.Sprintf(" INTO %s (%s) VALUES %s", mainTable, fields, v)
+	}
+	return query + " SELECT 1 FROM dual"
+}
+
+// Determine number of rows in a bulk INSERT query which doesn't exceed the limits of bind parameters and rows
+func insertChunkSize(compat Compat, columns int) int {
+	size := compat.Capabilities().MaxBinds / columns
+	if max := compat.Capabilities().MaxInsertRows; max > 0 && size > max {
+		size = max
+	}
+	return size
 }
@@ -3,7 +3,15 @@ package gqb_test
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ysugimoto/gqb"
 )
 
 type sqlResultMock struct{}
@@ -37,8 +45,494 @@ func (m *mockExecutor) ExecContext(ctx context.Context, query string, binds ...i
 	return nil, mockError{}
 }
 
+// syncMockExecutor records all queries with lock, this is used for concurrent query test
+type syncMockExecutor struct {
+	mu      sync.Mutex
+	queries []string
+}
+
+func (m *syncMockExecutor) QueryContext(ctx context.Context, query string, binds ...interface{}) (*sql.Rows, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.queries = append(m.queries, fmt.Sprint(query, binds))
+	return nil, mockError{}
+}
+func (m *syncMockExecutor) ExecContext(ctx context.Context, query string, binds ...interface{}) (sql.Result, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.queries = append(m.queries, fmt.Sprint(query, binds))
+	return nil, mockError{}
+}
+
+// affectedExecutor returns sql.Result which has specified affected rows
+type affectedExecutor struct {
+	mockExecutor
+	affected int64
+}
+
+type affectedResult int64
+
+func (a affectedResult) LastInsertId() (int64, error) {
+	return 0, nil
+}
+func (a affectedResult) RowsAffected() (int64, error) {
+	return int64(a), nil
+}
+
+func (m *affectedExecutor) ExecContext(ctx context.Context, query string, binds ...interface{}) (sql.Result, error) {
+	m.query = query
+	m.binds = binds
+	return affectedResult(m.affected), nil
+}
+
 func TestAllDatabases(t *testing.T) {
 	runMysqlTest(t)
 	runPostgresTest(t)
 	runSQLiteTest(t)
+	runSQLServerTest(t)
+	runOracleTest(t)
+	runClickHouseTest(t)
+	runMariaDBTest(t)
+	runCockroachTest(t)
+	runYugabyteTest(t)
+}
+
+func TestCloneBuilder(t *testing.T) {
+	gqb.SetDriver("mysql")
+
+	t.Run("Clone() doesn't affect to base builder", func(t *testing.T) {
+		m := &mockExecutor{}
+		base := gqb.New(m).Where("active", 1, gqb.Equal)
+		_, err := base.Clone().Where("id", 1, gqb.Equal).Get("users")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, "SELECT * FROM `users` WHERE (`active` = ?) AND (`id` = ?)", m.query)
+
+		_, err = base.OrderBy("id", gqb.Desc).Get("users")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, "SELECT * FROM `users` WHERE (`active` = ?) ORDER BY `id` DESC", m.query)
+	})
+
+	t.Run("Immutable() builder keeps state after chaining and execution", func(t *testing.T) {
+		m := &mockExecutor{}
+		base := gqb.New(m).Immutable().Where("active", 1, gqb.Equal)
+		base.Limit(10)
+		_, err := base.Where("id", 1, gqb.Equal).Get("users")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, "SELECT * FROM `users` WHERE (`active` = ?) AND (`id` = ?)", m.query)
+
+		_, err = base.Delete("users")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, "DELETE FROM `users` WHERE (`active` = ?)", m.query)
+	})
+
+	t.Run("Immutable() builder can be shared between goroutines", func(t *testing.T) {
+		m := &syncMockExecutor{}
+		base := gqb.New(m).Immutable().Where("active", 1, gqb.Equal)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				_, _ = base.Where("id", i, gqb.Equal).OrderBy("id", gqb.Asc).Get("users")
+			}(i)
+		}
+		wg.Wait()
+
+		expects := []string{}
+		for i := 0; i < 10; i++ {
+			expects = append(expects, fmt.Sprint("SELECT * FROM `users` WHERE (`active` = ?) AND (`id` = ?) ORDER BY `id` ASC", []interface{}{1, i}))
+		}
+		sort.Strings(expects)
+		sort.Strings(m.queries)
+		assert.Equal(t, expects, m.queries)
+	})
+}
+
+func TestMutationGuard(t *testing.T) {
+	gqb.SetDriver("mysql")
+
+	t.Run("Delete() without WHERE is refused", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).Delete("users")
+		assert.Equal(t, gqb.ErrUnsafeMutation, err)
+		assert.Equal(t, "", m.query)
+	})
+
+	t.Run("Update() without WHERE is refused", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).Update("users", gqb.Data{"name": "John"})
+		assert.Equal(t, gqb.ErrUnsafeMutation, err)
+		assert.Equal(t, "", m.query)
+	})
+
+	t.Run("Delete() with empty WhereGroup is refused", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			WhereGroup(func(g *gqb.WhereGroup) {
+				g.AddWhere(&gqb.WhereGroup{})
+			}).
+			Delete("users")
+		assert.Equal(t, gqb.ErrUnsafeMutation, err)
+		assert.Equal(t, "", m.query)
+	})
+
+	t.Run("Empty WhereGroup is not rendered", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			WhereGroup(func(g *gqb.WhereGroup) {}).
+			Where("id", 1, gqb.Equal).
+			OrWhereGroup(func(g *gqb.WhereGroup) {
+				g.AddWhere(&gqb.WhereGroup{}).Where("name", "John", gqb.Equal)
+			}).
+			Get("users")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, "SELECT * FROM `users` WHERE (`id` = ?) OR (`name` = ?)", m.query)
+
+		m = &mockExecutor{}
+		_, err = gqb.New(m).
+			WhereGroup(func(g *gqb.WhereGroup) {}).
+			Get("users")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, "SELECT * FROM `users`", m.query)
+	})
+
+	t.Run("AllowFullTable() allows mutation without WHERE", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).AllowFullTable().Delete("users")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, "DELETE FROM `users`", m.query)
+	})
+
+	t.Run("MaxAffected() returns error when affected rows exceed", func(t *testing.T) {
+		m := &affectedExecutor{affected: 3}
+		_, err := gqb.New(m).
+			Where("active", 0, gqb.Equal).
+			MaxAffected(2).
+			Delete("users")
+		assert.Equal(t, gqb.ErrTooManyRowsAffected, err)
+
+		r, err := gqb.New(m).
+			Where("active", 0, gqb.Equal).
+			MaxAffected(3).
+			Update("users", gqb.Data{"name": "John"})
+		assert.NoError(t, err)
+		affected, _ := r.RowsAffected()
+		assert.Equal(t, int64(3), affected)
+	})
+
+	t.Run("MaxAffected() checks affected rows of BulkUpdate", func(t *testing.T) {
+		m := &affectedExecutor{affected: 3}
+		data := []gqb.Data{
+			{"id": 1, "name": "John"},
+			{"id": 2, "name": "Jane"},
+		}
+		_, err := gqb.New(m).MaxAffected(2).BulkUpdate("users", "id", data)
+		assert.Equal(t, gqb.ErrTooManyRowsAffected, err)
+
+		_, err = gqb.New(m).MaxAffected(3).BulkUpdate("users", "id", data)
+		assert.NoError(t, err)
+	})
+}
+
+type retryableError struct {
+	Number uint16
+}
+
+func (e retryableError) Error() string {
+	return "deadlock"
+}
+
+type stateError struct {
+	state string
+}
+
+func (e stateError) Error() string {
+	return "serialization failure"
+}
+
+func (e stateError) SQLState() string {
+	return e.state
+}
+
+type wrappedError struct {
+	err error
+}
+
+func (e wrappedError) Error() string {
+	return "commit: " + e.err.Error()
+}
+
+func (e wrappedError) Unwrap() error {
+	return e.err
+}
+
+func TestIsRetryable(t *testing.T) {
+	t.Run("MySQL deadlock is retryable", func(t *testing.T) {
+		assert.True(t, gqb.IsRetryable(&retryableError{Number: 1213}))
+		assert.False(t, gqb.IsRetryable(&retryableError{Number: 1062}))
+	})
+
+	t.Run("Wrapped serialization failure is retryable", func(t *testing.T) {
+		assert.True(t, gqb.IsRetryable(wrappedError{err: stateError{state: "40001"}}))
+		assert.False(t, gqb.IsRetryable(stateError{state: "23505"}))
+		assert.False(t, gqb.IsRetryable(nil))
+	})
+}
+
+// customCompat is third party dialect which is based on PostgreSQL
+type customCompat struct {
+	gqb.PostgresCompat
+}
+
+func (c customCompat) Capabilities() gqb.Capabilities {
+	caps := c.PostgresCompat.Capabilities()
+	caps.Returning = gqb.ReturningNone
+	caps.MutationLimit = true
+	caps.RowID = ""
+	return caps
+}
+
+func TestDialectRegistry(t *testing.T) {
+	t.Run("SetDriver() returns error for unknown driver and keeps current dialect", func(t *testing.T) {
+		assert.NoError(t, gqb.SetDriver("mysql"))
+		assert.Error(t, gqb.SetDriver("unknown"))
+
+		m := &mockExecutor{}
+		_, err := gqb.New(m).Get("users")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, "SELECT * FROM `users`", m.query)
+	})
+
+	t.Run("RegisterDialect() plugs in custom dialect which builder consults", func(t *testing.T) {
+		gqb.RegisterDialect("custom", customCompat{})
+		assert.NoError(t, gqb.SetDriver("custom"))
+		defer gqb.SetDriver("mysql")
+
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			Where("active", 0, gqb.Equal).
+			OrderBy("id", gqb.Asc).
+			Limit(10).
+			Delete("users")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, `DELETE FROM "users" WHERE ("active" = $1) ORDER BY "id" ASC LIMIT 10`, m.query)
+
+		m = &mockExecutor{}
+		_, err = gqb.New(m).
+			InsertReturning(context.Background(), "users", gqb.Data{"name": "John"})
+		assert.Error(t, err)
+		assert.Equal(t, "", m.query)
+	})
+
+	t.Run("FullJoin() and Lock() consult capabilities", func(t *testing.T) {
+		assert.NoError(t, gqb.SetDriver("postgres"))
+		defer gqb.SetDriver("mysql")
+
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			FullJoin("profiles", "id", "user_id", gqb.Equal).
+			Where("users.id", 1, gqb.Equal).
+			Lock(gqb.LockForUpdateSkipLocked).
+			Get("users")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, `SELECT * FROM "users" FULL OUTER JOIN "profiles" ON ("users"."id" = "profiles"."user_id") WHERE ("users"."id" = $1) FOR UPDATE SKIP LOCKED`, m.query)
+
+		assert.NoError(t, gqb.SetDriver("mysql"))
+		m = &mockExecutor{}
+		_, err = gqb.New(m).
+			FullJoin("profiles", "id", "user_id", gqb.Equal).
+			Get("users")
+		assert.Error(t, err)
+		assert.Equal(t, "", m.query)
+	})
+}
+
+// fakeDriver is database/sql driver which is used for dialect detection
+type fakeDriver struct{}
+
+func (d fakeDriver) Open(name string) (driver.Conn, error) {
+	return nil, fmt.Errorf("fake driver doesn't connect")
+}
+
+// driverExecutor is mockExecutor which exposes driver like *sql.DB
+type driverExecutor struct {
+	mockExecutor
+	driver driver.Driver
+}
+
+func (m *driverExecutor) Driver() driver.Driver {
+	return m.driver
+}
+
+func TestDetectDialect(t *testing.T) {
+	gqb.RegisterDriverType(fakeDriver{}, "postgres")
+	defer gqb.SetDriver("mysql")
+
+	t.Run("New() detects dialect from driver type", func(t *testing.T) {
+		gqb.SetDriver("mysql")
+		m := &driverExecutor{driver: fakeDriver{}}
+		_, err := gqb.New(m).Where("id", 1, gqb.Equal).Get("users")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, `SELECT * FROM "users" WHERE ("id" = $1)`, m.query)
+	})
+
+	t.Run("New() accepts typed nil executor", func(t *testing.T) {
+		var db *sql.DB
+		assert.NotPanics(t, func() {
+			gqb.New(db)
+		})
+	})
+
+	t.Run("Detected dialect doesn't change dialect of other builders", func(t *testing.T) {
+		gqb.SetDriver("mysql")
+		gqb.New(&driverExecutor{driver: fakeDriver{}})
+
+		m := &mockExecutor{}
+		_, err := gqb.New(m).Where("id", 1, gqb.Equal).Get("users")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, "SELECT * FROM `users` WHERE (`id` = ?)", m.query)
+	})
+
+	t.Run("Dialect() option overrides detection", func(t *testing.T) {
+		gqb.SetDriver("sqlite")
+		m := &driverExecutor{driver: fakeDriver{}}
+		_, err := gqb.New(m, gqb.Dialect("mysql")).Get("users")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, "SELECT * FROM `users`", m.query)
+
+		o := &mockExecutor{}
+		_, err = gqb.New(o).Get("users")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, `SELECT * FROM "users"`, o.query)
+	})
+
+	t.Run("Builders keep their own dialects concurrently", func(t *testing.T) {
+		gqb.SetDriver("mysql")
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				m := &driverExecutor{driver: fakeDriver{}}
+				gqb.New(m).Where("id", 1, gqb.Equal).Get("users")
+				assert.Equal(t, `SELECT * FROM "users" WHERE ("id" = $1)`, m.query)
+			}()
+			go func() {
+				defer wg.Done()
+				m := &mockExecutor{}
+				gqb.New(m).Where("id", 1, gqb.Equal).Get("users")
+				assert.Equal(t, "SELECT * FROM `users` WHERE (`id` = ?)", m.query)
+			}()
+		}
+		wg.Wait()
+	})
+
+	t.Run("Dialect() option with unknown name returns error on execution", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m, gqb.Dialect("unknown")).Get("users")
+		assert.Error(t, err)
+		assert.Equal(t, "", m.query)
+	})
+}
+
+// rowsDriver is database/sql driver which returns prepared result sets in order, it is used for testing query results
+type rowsDriver struct{}
+
+var rowsConns sync.Map
+
+func init() {
+	sql.Register("gqb-rows", rowsDriver{})
+}
+
+func (d rowsDriver) Open(name string) (driver.Conn, error) {
+	c, ok := rowsConns.Load(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown connection %s", name)
+	}
+	return c.(*rowsConn), nil
+}
+
+// rowsResult is result set which is returned for query
+type rowsResult struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+// rowsConn records queries and bind parameters, and returns result sets in order
+type rowsConn struct {
+	mu      sync.Mutex
+	queries []string
+	binds   [][]driver.Value
+	results []rowsResult
+}
+
+func (c *rowsConn) Prepare(query string) (driver.Stmt, error) {
+	return &rowsStmt{conn: c, query: query}, nil
+}
+func (c *rowsConn) Close() error {
+	return nil
+}
+func (c *rowsConn) Begin() (driver.Tx, error) {
+	return nil, fmt.Errorf("rows driver doesn't support transaction")
+}
+
+type rowsStmt struct {
+	conn  *rowsConn
+	query string
+}
+
+func (s *rowsStmt) Close() error {
+	return nil
+}
+func (s *rowsStmt) NumInput() int {
+	return -1
+}
+func (s *rowsStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return nil, fmt.Errorf("rows driver doesn't support exec")
+}
+func (s *rowsStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.conn.mu.Lock()
+	defer s.conn.mu.Unlock()
+	s.conn.queries = append(s.conn.queries, s.query)
+	s.conn.binds = append(s.conn.binds, args)
+	if len(s.conn.results) == 0 {
+		return nil, fmt.Errorf("no more result sets for %s", s.query)
+	}
+	r := s.conn.results[0]
+	s.conn.results = s.conn.results[1:]
+	return &rowsCursor{result: r}, nil
+}
+
+type rowsCursor struct {
+	result rowsResult
+	index  int
+}
+
+func (r *rowsCursor) Columns() []string {
+	return r.result.columns
+}
+func (r *rowsCursor) Close() error {
+	return nil
+}
+func (r *rowsCursor) Next(dest []driver.Value) error {
+	if r.index >= len(r.result.values) {
+		return io.EOF
+	}
+	copy(dest, r.result.values[r.index])
+	r.index++
+	return nil
+}
+
+// Create *sql.DB which returns result sets in order, and connection which records queries
+func openRows(t *testing.T, results ...rowsResult) (*sql.DB, *rowsConn) {
+	name := t.Name()
+	c := &rowsConn{results: results}
+	rowsConns.Store(name, c)
+	db, err := sql.Open("gqb-rows", name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(1)
+	return db, c
 }
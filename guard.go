@@ -0,0 +1,93 @@
+package gqb
+
+import (
+	"context"
+	"errors"
+
+	"database/sql"
+)
+
+var (
+	// ErrUnsafeMutation is returned when UPDATE or DELETE query is executed without any WHERE condition.
+	// Call AllowFullTable() explicitly if you need to mutate all rows.
+	ErrUnsafeMutation = errors.New("UPDATE/DELETE query without WHERE condition is refused")
+
+	// ErrTooManyRowsAffected is returned when affected rows exceed the number which is specified by MaxAffected()
+	ErrTooManyRowsAffected = errors.New("affected rows exceed the maximum number")
+)
+
+// txBeginner is interface which can begin transaction like *sql.DB
+type txBeginner interface {
+	BeginTx(context.Context, *sql.TxOptions) (*sql.Tx, error)
+}
+
+// Allow UPDATE/DELETE query without WHERE condition
+func (q *QueryBuilder) AllowFullTable() *QueryBuilder {
+	q = q.derive()
+	q.allowFullTable = true
+	return q
+}
+
+// Set maximum number of affected rows for UPDATE/DELETE query and BulkUpdate().
+// If executor can begin transaction like *sql.DB, query runs in transaction and is rolled back when exceeded.
+// If executor is already in transaction like *sql.Tx, ErrTooManyRowsAffected is returned so caller should roll back it.
+func (q *QueryBuilder) MaxAffected(max int64) *QueryBuilder {
+	q = q.derive()
+	q.maxAffected = max
+	return q
+}
+
+// Check mutation query is safe
+func (q *QueryBuilder) checkMutation() error {
+	if !hasConditions(q.compat, q.wheres) && !q.allowFullTable {
+		return ErrUnsafeMutation
+	}
+	return nil
+}
+
+// Execute mutation query with checking affected rows
+func (q *QueryBuilder) execMutation(ctx context.Context, query string, binds []interface{}) (sql.Result, error) {
+	if q.maxAffected <= 0 {
+		return q.db.ExecContext(ctx, query, binds...)
+	}
+	b, ok := q.db.(txBeginner)
+	if !ok {
+		result, err := q.db.ExecContext(ctx, query, binds...)
+		if err != nil {
+			return nil, err
+		}
+		if err := q.checkAffected(result); err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+
+	tx, err := b.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	result, err := tx.ExecContext(ctx, query, binds...)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := q.checkAffected(result); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Check affected rows don't exceed the maximum number
+func (q *QueryBuilder) checkAffected(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	} else if affected > q.maxAffected {
+		return ErrTooManyRowsAffected
+	}
+	return nil
+}
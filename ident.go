@@ -0,0 +1,313 @@
+package gqb
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Identifier part which is accepted in strict mode, parts are separated by dot
+var strictIdentifier = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// identPart is a part of qualified identifier like "table" of "table.column".
+// exact indicates the part is quoted already, so it must be quoted as it is.
+type identPart struct {
+	name  string
+	exact bool
+}
+
+// identQuoter is optional interface of Compat which quotes single identifier part exactly, even if it contains dot.
+// Compat which doesn't implement it quotes the part by Quote().
+type identQuoter interface {
+	QuoteIdent(part string) string
+}
+
+// Ident is identifier which consists of parts like Ident{"schema", "table", "column"}.
+// Each part is quoted exactly even if it contains dot or quote characters,
+// and the identifier is accepted as table or field name of Select(), Where(), Join(), OrderBy(), GroupBy(), Alias() and Get().
+type Ident []string
+
+// Quote each part of identifier exactly on the dialect
+func (i Ident) quote(compat Compat) string {
+	parts := []string{}
+	for _, p := range i {
+		if q, ok := compat.(identQuoter); ok {
+			parts = append(parts, q.QuoteIdent(p))
+		} else {
+			parts = append(parts, compat.Quote(p))
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// Split identifier by dots which are outside of quoted parts.
+// Part which is wrapped by open and close characters is unwrapped and unescaped.
+func splitIdentifier(str, open, close string) []identPart {
+	parts := []identPart{}
+	for {
+		var p identPart
+		var rest string
+		ok := false
+		if open != "" {
+			p, rest, ok = splitQuotedPart(str, open, close)
+		}
+		if !ok {
+			if index := strings.Index(str, "."); index != -1 {
+				p, rest = identPart{name: str[:index]}, str[index:]
+			} else {
+				p, rest = identPart{name: str}, ""
+			}
+		}
+		parts = append(parts, p)
+		if rest == "" {
+			return parts
+		}
+		str = rest[1:]
+	}
+}
+
+// Split leading quoted part, doubled close characters inside of the part are unescaped.
+// ok is false if str doesn't start with quoted part which is followed by dot or end of string.
+func splitQuotedPart(str, open, close string) (identPart, string, bool) {
+	if !strings.HasPrefix(str, open) {
+		return identPart{}, "", false
+	}
+	var name string
+	rest := str[len(open):]
+	for {
+		index := strings.Index(rest, close)
+		if index == -1 {
+			return identPart{}, "", false
+		}
+		name += rest[:index]
+		rest = rest[index+len(close):]
+		if !strings.HasPrefix(rest, close) {
+			break
+		}
+		name += close
+		rest = rest[len(close):]
+	}
+	if rest != "" && !strings.HasPrefix(rest, ".") {
+		return identPart{}, "", false
+	}
+	return identPart{name: name, exact: true}, rest, true
+}
+
+// Quote identifier which may be qualified like "table.column" with open and close quote characters.
+// Quoted parts are kept as they are, and fold converts other parts like upper case on Oracle.
+// Embedded close characters are escaped by doubling, so identifier can't break out of quoting.
+func quoteIdentifier(str, open, close string, fold func(string) string) string {
+	if str == "" {
+		return str
+	}
+	parts := []string{}
+	for _, p := range splitIdentifier(str, open, close) {
+		name := p.name
+		if !p.exact && fold != nil {
+			name = fold(name)
+		}
+		parts = append(parts, quotePart(name, open, close))
+	}
+	return strings.Join(parts, ".")
+}
+
+// Quote single identifier part with escaping close characters
+func quotePart(name, open, close string) string {
+	return open + strings.Replace(name, close, close+close, -1) + close
+}
+
+// Get alias name as identifier which isn't split by dots.
+// Name without dots is kept as string in order to be quoted like other names, e.g. upper case on Oracle.
+func aliasIdent(name string) interface{} {
+	if strings.Contains(name, ".") {
+		return Ident{name}
+	}
+	return name
+}
+
+// Check value can be quoted as identifier, it must be string, Ident or Raw
+func checkIdentType(v interface{}) error {
+	switch v.(type) {
+	case string, Ident, Raw:
+		return nil
+	}
+	return fmt.Errorf("identifier must be string, Ident or Raw, but %T is given", v)
+}
+
+// Get names of identifier parts, Raw is not an identifier so it returns nothing
+func identNames(v interface{}) []string {
+	names := []string{}
+	switch t := v.(type) {
+	case Ident:
+		names = append(names, t...)
+	case string:
+		for _, p := range splitIdentifier(t, "", "") {
+			names = append(names, p.name)
+		}
+	}
+	return names
+}
+
+// Get the last part of identifier, it is column name of result
+func lastIdentPart(v interface{}) string {
+	if r, ok := v.(Raw); ok {
+		return string(r)
+	}
+	names := identNames(v)
+	if len(names) == 0 {
+		return ""
+	}
+	return names[len(names)-1]
+}
+
+// Default schema which qualifies unqualified table names
+var (
+	searchSchemaMu sync.RWMutex
+	searchSchema   string
+)
+
+// Set default schema which qualifies unqualified table names, e.g. "app" makes "users" table "app"."users".
+// This is useful for PostgreSQL which has tables in multiple schemas. Empty schema disables qualifying.
+func SetSearchSchema(schema string) {
+	searchSchemaMu.Lock()
+	defer searchSchemaMu.Unlock()
+	searchSchema = schema
+}
+
+// Qualify table name with search schema if table name isn't qualified, table is string or Ident
+func schemaTable(table interface{}) interface{} {
+	searchSchemaMu.RLock()
+	schema := searchSchema
+	searchSchemaMu.RUnlock()
+	if schema == "" {
+		return table
+	}
+	switch t := table.(type) {
+	case Ident:
+		if len(t) == 1 {
+			return Ident{schema, t[0]}
+		}
+	case string:
+		if t != "" && len(splitIdentifier(t, "", "")) == 1 {
+			return schema + "." + t
+		}
+	}
+	return table
+}
+
+// Reject identifiers which have characters outside of [A-Za-z0-9_.] on execution, each part of Ident is checked too.
+// This is useful when table or column names come from outside like request parameters.
+// Note that Raw is not checked.
+func StrictIdentifiers() Option {
+	return func(q *QueryBuilder) {
+		q.strict = true
+	}
+}
+
+// Check identifier names in strict mode
+func (q *QueryBuilder) checkIdentifiers(names ...string) error {
+	idents := make([]interface{}, len(names))
+	for i, n := range names {
+		idents[i] = n
+	}
+	return q.checkIdents(idents...)
+}
+
+// Check identifiers like string or Ident in strict mode
+func (q *QueryBuilder) checkIdents(idents ...interface{}) error {
+	if !q.strict {
+		return nil
+	}
+	for _, v := range idents {
+		for _, name := range identNames(v) {
+			if !strictIdentifier.MatchString(name) {
+				return fmt.Errorf("Identifier %q is not allowed in strict mode", name)
+			}
+		}
+	}
+	return nil
+}
+
+// Collect identifiers which are used in query, it is empty if strict mode is disabled
+func (q *QueryBuilder) identifiers(table interface{}) []interface{} {
+	if !q.strict {
+		return nil
+	}
+	idents := []interface{}{}
+	if t, ok := table.(alias); ok {
+		idents = append(idents, t.from, t.to)
+	} else {
+		idents = append(idents, table)
+	}
+	idents = append(idents, q.selects...)
+	for _, j := range q.joins {
+		if t, ok := j.table.(alias); ok {
+			idents = append(idents, t.from, t.to)
+		} else {
+			idents = append(idents, j.table)
+		}
+		idents = append(idents, j.on.field, j.on.value)
+	}
+	for _, w := range q.wheres {
+		idents = append(idents, conditionIdentifiers(w)...)
+	}
+	idents = append(idents, q.groupBy...)
+	for _, o := range q.orders {
+		idents = append(idents, o.field)
+	}
+	if q.limitBy != nil {
+		idents = append(idents, q.limitBy.fields...)
+	}
+	return idents
+}
+
+// Collect identifiers of condition recursively
+func conditionIdentifiers(c ConditionBuilder) []interface{} {
+	idents := []interface{}{}
+	switch v := c.(type) {
+	case condition:
+		idents = append(idents, v.field)
+	case *WhereGroup:
+		for _, cd := range v.conditions {
+			idents = append(idents, conditionIdentifiers(cd)...)
+		}
+	case conditionList:
+		for _, cd := range v.conditions {
+			idents = append(idents, conditionIdentifiers(cd)...)
+		}
+	case keysetCondition:
+		for _, o := range v.orders {
+			idents = append(idents, o.field)
+		}
+	}
+	return idents
+}
+
+// Create Scope which adds ORDER BY clause for user specified sort field like request parameter.
+// field is column name which is prefixed by "-" for descending order, e.g. "-created_at",
+// and field must be one of allowed, otherwise the builder returns error on execution.
+// Empty field adds nothing.
+func SafeOrderBy(field string, allowed ...string) Scope {
+	return func(q *QueryBuilder) *QueryBuilder {
+		if field == "" {
+			return q
+		}
+		sort := Asc
+		name := strings.TrimPrefix(field, "+")
+		if strings.HasPrefix(name, "-") {
+			sort = Desc
+			name = name[1:]
+		}
+		for _, a := range allowed {
+			if a == name {
+				return q.OrderBy(name, sort)
+			}
+		}
+		q = q.derive()
+		if q.err == nil {
+			q.err = fmt.Errorf("Sort field %q is not allowed", field)
+		}
+		return q
+	}
+}
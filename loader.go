@@ -0,0 +1,380 @@
+package gqb
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"database/sql"
+)
+
+// RowSource is interface which streams rows for bulk loading.
+// Next() returns values of the next row with the same order of columns, and returns io.EOF when there are no more rows.
+type RowSource interface {
+	Next() ([]interface{}, error)
+}
+
+// Loader is interface for bulk loading which streams rows into table.
+// table is string or Ident, and Load() returns the number of loaded rows.
+type Loader interface {
+	Load(ctx context.Context, db Executor, table interface{}, columns []string, rows RowSource) (int64, error)
+}
+
+// sliceRows is RowSource implementation for slice
+type sliceRows struct {
+	rows  [][]interface{}
+	index int
+}
+
+// Create RowSource from slice of row values
+func RowsFromSlice(rows [][]interface{}) RowSource {
+	return &sliceRows{rows: rows}
+}
+
+// RowSource interface implementation
+func (s *sliceRows) Next() ([]interface{}, error) {
+	if s.index >= len(s.rows) {
+		return nil, io.EOF
+	}
+	s.index++
+	return s.rows[s.index-1], nil
+}
+
+// dialectKey is context key for dialect of the builder which runs loader
+type dialectKey struct{}
+
+// Get dialect of the builder which runs loader, default dialect is used if loader is called directly
+func loaderDialect(ctx context.Context) Compat {
+	if c, ok := ctx.Value(dialectKey{}).(Compat); ok {
+		return c
+	}
+	return defaultDialect()
+}
+
+// BulkLoader option replaces loader which is used for BulkLoad()
+func BulkLoader(l Loader) Option {
+	return func(q *QueryBuilder) {
+		q.loader = l
+	}
+}
+
+// Execute bulk loading which streams rows into table through the loader.
+// The loader is Loader of driver's capabilities like PostgresCopyLoader for PostgreSQL,
+// or InsertLoader if driver doesn't have it, unless BulkLoader option is specified.
+// Note that rows are loaded as they are, so timestamps are not applied.
+// Under tenant policy, tenant value is appended to each row, or each row must have the tenant value if columns contain tenant column.
+// Call Atomic() to run loading in a single transaction.
+func (q *QueryBuilder) BulkLoad(ctx context.Context, table interface{}, columns []string, rows RowSource) (int64, error) {
+	q = q.derive()
+	defer q.Reset()
+	switch t := table.(type) {
+	case string:
+		if t == "" {
+			return 0, fmt.Errorf("Table name must not be empty")
+		}
+	case Ident:
+		if len(t) == 0 {
+			return 0, fmt.Errorf("Table name must not be empty")
+		}
+	default:
+		return 0, fmt.Errorf("Invalid table specified")
+	}
+	if len(columns) == 0 {
+		return 0, fmt.Errorf("columns must not be empty")
+	} else if err := q.checkIdents(table); err != nil {
+		return 0, err
+	} else if err := q.checkIdentifiers(columns...); err != nil {
+		return 0, err
+	}
+	columns, rows, err := q.tenantRows(ctx, table, columns, rows)
+	if err != nil {
+		return 0, err
+	}
+	table = schemaTable(table)
+	ctx = context.WithValue(ctx, dialectKey{}, q.compat)
+	loader := q.loader
+	if loader == nil {
+		loader = defaultLoader(q.compat)
+	}
+
+	b, ok := q.db.(txBeginner)
+	if !q.atomic || !ok {
+		return loader.Load(ctx, q.db, table, columns, rows)
+	}
+	tx, err := b.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	loaded, err := loader.Load(ctx, tx, table, columns, rows)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return loaded, nil
+}
+
+// Get default loader for the driver
+func defaultLoader(compat Compat) Loader {
+	if l := compat.Capabilities().Loader; l != nil {
+		return l
+	}
+	return InsertLoader{}
+}
+
+// InsertLoader is Loader implementation with chunked bulk INSERT query.
+// This is available for all drivers.
+type InsertLoader struct{}
+
+// Loader interface implementation
+func (l InsertLoader) Load(ctx context.Context, db Executor, table interface{}, columns []string, rows RowSource) (int64, error) {
+	if len(columns) == 0 {
+		return 0, fmt.Errorf("columns must not be empty")
+	}
+	compat := loaderDialect(ctx)
+	size := insertChunkSize(compat, len(columns))
+	if size == 0 {
+		return 0, fmt.Errorf("columns are too many to insert")
+	}
+	var loaded int64
+	for {
+		data, err := readData(rows, columns, size)
+		if err != nil {
+			return loaded, err
+		} else if len(data) == 0 {
+			return loaded, nil
+		}
+		result, err := (&QueryBuilder{db: db, compat: compat}).BulkInsertContext(ctx, table, data)
+		if err != nil {
+			return loaded, err
+		}
+		if affected, err := result.RowsAffected(); err == nil {
+			loaded += affected
+		}
+	}
+}
+
+// Read rows up to size as Data
+func readData(rows RowSource, columns []string, size int) ([]Data, error) {
+	data := []Data{}
+	for len(data) < size {
+		values, err := rows.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		} else if len(values) != len(columns) {
+			return nil, fmt.Errorf("row has %d values but %d columns are specified", len(values), len(columns))
+		}
+		d := Data{}
+		for i, c := range columns {
+			d[c] = values[i]
+		}
+		data = append(data, d)
+	}
+	return data, nil
+}
+
+// preparer is interface which can create prepared statement like *sql.DB and *sql.Tx
+type preparer interface {
+	PrepareContext(context.Context, string) (*sql.Stmt, error)
+}
+
+// PostgresCopyLoader is Loader implementation with "COPY FROM STDIN".
+// This works with the driver which supports COPY through prepared statement like github.com/lib/pq,
+// so it is default loader of "pq" dialect which is detected for lib/pq, and PostgresCompat{Copy: true}.
+// If executor can begin transaction like *sql.DB, loading runs in transaction because COPY requires the same connection.
+type PostgresCopyLoader struct{}
+
+// Loader interface implementation
+func (l PostgresCopyLoader) Load(ctx context.Context, db Executor, table interface{}, columns []string, rows RowSource) (int64, error) {
+	compat := loaderDialect(ctx)
+	fields := []string{}
+	for _, c := range columns {
+		fields = append(fields, quote(compat, c))
+	}
+	query := fmt.Sprintf(
+		"COPY %s (%s) FROM STDIN",
+		quote(compat, table),
+		strings.Join(fields, ", "),
+	)
+	// Flush buffered rows by executing without arguments
+	return loadStatement(ctx, compat, db, query, columns, rows, true)
+}
+
+// ClickHouseBatchLoader is Loader implementation with batch INSERT of ClickHouse.
+// This works with the driver which sends rows of prepared INSERT statement as a block like github.com/ClickHouse/clickhouse-go.
+// If executor can begin transaction like *sql.DB, loading runs in transaction because driver sends the block on commit.
+type ClickHouseBatchLoader struct{}
+
+// Loader interface implementation
+func (l ClickHouseBatchLoader) Load(ctx context.Context, db Executor, table interface{}, columns []string, rows RowSource) (int64, error) {
+	compat := loaderDialect(ctx)
+	fields := []string{}
+	for _, c := range columns {
+		fields = append(fields, quote(compat, c))
+	}
+	query := fmt.Sprintf(
+		"INSERT INTO %s (%s)",
+		quote(compat, table),
+		strings.Join(fields, ", "),
+	)
+	return loadStatement(ctx, compat, db, query, columns, rows, false)
+}
+
+// loadStatement() executes prepared statement for each row in transaction.
+// If flush is true, statement is executed without arguments at the end.
+func loadStatement(ctx context.Context, compat Compat, db Executor, query string, columns []string, rows RowSource, flush bool) (int64, error) {
+	if b, ok := db.(txBeginner); ok {
+		tx, err := b.BeginTx(ctx, nil)
+		if err != nil {
+			return 0, err
+		}
+		loaded, err := loadStatement(ctx, compat, tx, query, columns, rows, flush)
+		if err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+		return loaded, tx.Commit()
+	}
+
+	p, ok := db.(preparer)
+	if !ok {
+		return 0, fmt.Errorf("executor doesn't support prepared statement for bulk loading")
+	}
+	stmt, err := p.PrepareContext(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	var loaded int64
+	for {
+		values, err := rows.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return 0, err
+		} else if len(values) != len(columns) {
+			return 0, fmt.Errorf("row has %d values but %d columns are specified", len(values), len(columns))
+		}
+		binds := []interface{}{}
+		for _, v := range values {
+			binds = bind(compat, binds, v)
+		}
+		if _, err := stmt.ExecContext(ctx, binds...); err != nil {
+			return 0, err
+		}
+		loaded++
+	}
+	if flush {
+		if _, err := stmt.ExecContext(ctx); err != nil {
+			return 0, err
+		}
+	}
+	return loaded, nil
+}
+
+// readerSequence is sequence number for reader handler name
+var readerSequence uint64
+
+// MysqlLoadDataLoader is Loader implementation with "LOAD DATA LOCAL INFILE".
+// Register and Deregister should be reader handler functions of the driver,
+// e.g. mysql.RegisterReaderHandler and mysql.DeregisterReaderHandler of github.com/go-sql-driver/mysql.
+type MysqlLoadDataLoader struct {
+	Register   func(name string, handler func() io.Reader)
+	Deregister func(name string)
+}
+
+// Loader interface implementation
+func (l MysqlLoadDataLoader) Load(ctx context.Context, db Executor, table interface{}, columns []string, rows RowSource) (int64, error) {
+	if l.Register == nil || l.Deregister == nil {
+		return 0, fmt.Errorf("reader handler functions must be specified")
+	}
+	name := fmt.Sprintf("gqb_%d", atomic.AddUint64(&readerSequence, 1))
+	pr, pw := io.Pipe()
+	l.Register(name, func() io.Reader {
+		return pr
+	})
+	defer l.Deregister(name)
+
+	var loaded int64
+	go func() {
+		pw.CloseWithError(writeTSV(pw, columns, rows, &loaded))
+	}()
+
+	compat := loaderDialect(ctx)
+	fields := []string{}
+	for _, c := range columns {
+		fields = append(fields, quote(compat, c))
+	}
+	result, err := db.ExecContext(ctx, fmt.Sprintf(
+		`LOAD DATA LOCAL INFILE 'Reader::%s' INTO TABLE %s FIELDS TERMINATED BY '\t' ESCAPED BY '\\' LINES TERMINATED BY '\n' (%s)`,
+		name,
+		quote(compat, table),
+		strings.Join(fields, ", "),
+	))
+	// Close reader in order to stop writing goroutine if driver doesn't read all
+	pr.Close()
+	if err != nil {
+		return 0, err
+	}
+	if affected, err := result.RowsAffected(); err == nil {
+		return affected, nil
+	}
+	return atomic.LoadInt64(&loaded), nil
+}
+
+// writeTSV() writes rows as tab separated values which is default format of LOAD DATA
+func writeTSV(w io.Writer, columns []string, rows RowSource, loaded *int64) error {
+	for {
+		values, err := rows.Next()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		} else if len(values) != len(columns) {
+			return fmt.Errorf("row has %d values but %d columns are specified", len(values), len(columns))
+		}
+		var buf bytes.Buffer
+		for i, v := range values {
+			if i > 0 {
+				buf.WriteByte('\t')
+			}
+			buf.WriteString(formatTSVValue(v))
+		}
+		buf.WriteByte('\n')
+		if _, err := w.Write(buf.Bytes()); err != nil {
+			return err
+		}
+		atomic.AddInt64(loaded, 1)
+	}
+}
+
+// formatTSVValue() formats value with escaping for LOAD DATA
+func formatTSVValue(v interface{}) string {
+	var s string
+	switch t := v.(type) {
+	case nil:
+		return `\N`
+	case time.Time:
+		s = t.Format(datetimeFormat)
+	case []byte:
+		s = string(t)
+	case bool:
+		if t {
+			s = "1"
+		} else {
+			s = "0"
+		}
+	default:
+		s = fmt.Sprint(t)
+	}
+	return strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`).Replace(s)
+}
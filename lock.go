@@ -0,0 +1,72 @@
+package gqb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"database/sql"
+)
+
+// ErrStaleObject is returned when UPDATE query with optimistic lock doesn't update any rows.
+// It means that the row has been updated or deleted by another process after reading.
+var ErrStaleObject = errors.New("row has been modified by another process")
+
+// increment is UPDATE value which increments current value of the column like "version = version + 1"
+type increment string
+
+// OptimisticLock option enables optimistic locking with specified version column.
+// UPDATE query compares version column with value in Data, and increments version column.
+func OptimisticLock(column string) Option {
+	return func(q *QueryBuilder) {
+		q.lockColumn = column
+	}
+}
+
+// Create copied Data which increments version column, and version condition.
+// Returns nil condition if optimistic lock is disabled.
+func (q *QueryBuilder) lockData(data Data) (Data, ConditionBuilder, error) {
+	if q.lockColumn == "" {
+		return data, nil, nil
+	}
+	version, ok := data[q.lockColumn]
+	if !ok || version == nil {
+		return nil, nil, fmt.Errorf("update data must have current version value for %s", q.lockColumn)
+	}
+	locked := Data{}
+	for k, v := range data {
+		locked[k] = v
+	}
+	locked[q.lockColumn] = increment(q.lockColumn)
+	return locked, condition{
+		comparison: Equal,
+		field:      q.lockColumn,
+		value:      version,
+		combine:    And,
+	}, nil
+}
+
+// Check UPDATE query with optimistic lock updates any rows
+func checkStale(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	} else if affected == 0 {
+		return ErrStaleObject
+	}
+	return nil
+}
+
+// Execute UPDATE query with struct fields which have "db" tag
+func (q *QueryBuilder) UpdateStruct(table interface{}, src interface{}) (sql.Result, error) {
+	return q.UpdateStructContext(context.Background(), table, src)
+}
+
+// Execute UPDATE query with struct fields which have "db" tag with context
+func (q *QueryBuilder) UpdateStructContext(ctx context.Context, table interface{}, src interface{}) (sql.Result, error) {
+	data, err := NewData(src)
+	if err != nil {
+		return nil, err
+	}
+	return q.UpdateContext(ctx, table, data)
+}
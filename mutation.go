@@ -0,0 +1,172 @@
+package gqb
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Get table name which is used for qualifying columns, it is alias name if table is aliased
+func targetTable(table interface{}) interface{} {
+	if v, ok := table.(alias); ok {
+		return aliasIdent(v.to)
+	}
+	return schemaTable(table)
+}
+
+// Create UPDATE query with considering joins, orders, limit and offset by driver's capabilities.
+//
+// MySQL       -> UPDATE t JOIN j ON (...) SET ... WHERE ..., or UPDATE t SET ... WHERE ... ORDER BY ... LIMIT n
+// PostgreSQL  -> UPDATE t SET ... FROM j WHERE (...) AND ..., or UPDATE t SET ... WHERE ctid IN (SELECT ...)
+// CockroachDB -> UPDATE t SET ... FROM j WHERE (...) AND ..., or UPDATE t SET ... WHERE ... ORDER BY ... LIMIT n
+// SQLite      -> UPDATE t SET ... WHERE rowid IN (SELECT ...)
+// ClickHouse  -> ALTER TABLE t UPDATE ... WHERE ...
+func (q *QueryBuilder) buildUpdateQuery(table interface{}, mainTable, updates string, wheres []ConditionBuilder, binds []interface{}) (string, []interface{}, error) {
+	target := targetTable(table)
+	caps := q.compat.Capabilities()
+	if caps.Mutation == MutationAlterTable {
+		return q.buildAlterMutation(table, mainTable, "UPDATE "+updates, wheres, binds)
+	}
+
+	prefix := fmt.Sprintf("UPDATE %s SET %s", mainTable, updates)
+	switch q.mutationPlan(caps) {
+	case planInlineJoin:
+		where, binds := buildWhere(q.compat, wheres, binds)
+		return fmt.Sprintf("UPDATE %s%s SET %s%s", mainTable, buildJoin(q.compat, q.joins, target), updates, where), binds, nil
+	case planFromJoin:
+		return q.buildFromJoinMutation(prefix, "FROM", target, wheres, binds)
+	case planRowSubquery:
+		return q.buildRowSubqueryMutation(prefix, caps.RowID, mainTable, target, wheres, binds)
+	case planUnsupported:
+		return "", nil, fmt.Errorf("JOIN, ORDER BY, LIMIT and OFFSET of this UPDATE query are not supported on this driver")
+	}
+	where, binds := buildWhere(q.compat, wheres, binds)
+	return prefix + where + buildOrderBy(q.compat, q.orders) + buildLimit(q.limit), binds, nil
+}
+
+// Create DELETE query with considering joins, orders, limit and offset by driver's capabilities.
+//
+// MySQL       -> DELETE t FROM t JOIN j ON (...) WHERE ..., or DELETE FROM t WHERE ... ORDER BY ... LIMIT n
+// PostgreSQL  -> DELETE FROM t USING j WHERE (...) AND ..., or DELETE FROM t WHERE ctid IN (SELECT ...)
+// CockroachDB -> DELETE FROM t USING j WHERE (...) AND ..., or DELETE FROM t WHERE ... ORDER BY ... LIMIT n
+// SQLite      -> DELETE FROM t WHERE rowid IN (SELECT ...)
+// ClickHouse  -> ALTER TABLE t DELETE WHERE ...
+func (q *QueryBuilder) buildDeleteQuery(table interface{}, mainTable string, wheres []ConditionBuilder) (string, []interface{}, error) {
+	target := targetTable(table)
+	binds := []interface{}{}
+	caps := q.compat.Capabilities()
+	if caps.Mutation == MutationAlterTable {
+		return q.buildAlterMutation(table, mainTable, "DELETE", wheres, binds)
+	}
+
+	prefix := "DELETE FROM " + mainTable
+	switch q.mutationPlan(caps) {
+	case planInlineJoin:
+		where, binds := buildWhere(q.compat, wheres, binds)
+		return fmt.Sprintf("DELETE %s FROM %s%s%s", quote(q.compat, target), mainTable, buildJoin(q.compat, q.joins, target), where), binds, nil
+	case planFromJoin:
+		return q.buildFromJoinMutation(prefix, "USING", target, wheres, binds)
+	case planRowSubquery:
+		return q.buildRowSubqueryMutation(prefix, caps.RowID, mainTable, target, wheres, binds)
+	case planUnsupported:
+		return "", nil, fmt.Errorf("JOIN, ORDER BY, LIMIT and OFFSET of this DELETE query are not supported on this driver")
+	}
+	where, binds := buildWhere(q.compat, wheres, binds)
+	return prefix + where + buildOrderBy(q.compat, q.orders) + buildLimit(q.limit), binds, nil
+}
+
+// mutationPlan is how UPDATE/DELETE query expresses joins, orders, limit and offset
+type mutationPlan int
+
+const (
+	// planNative writes orders and limit to query directly, or there are nothing to express
+	planNative mutationPlan = iota
+	planInlineJoin
+	planFromJoin
+	planRowSubquery
+	planUnsupported
+)
+
+// Determine mutation plan by capabilities.
+// Native syntax is preferred, and row identifier subquery is used if native syntax can't express the query.
+func (q *QueryBuilder) mutationPlan(caps Capabilities) mutationPlan {
+	limited := q.needsRowSubquery()
+	joined := len(q.joins) > 0
+	for _, j := range q.joins {
+		// Outer join can't be expressed as target rows of mutation
+		if j.full {
+			return planUnsupported
+		}
+	}
+	switch {
+	case !limited && !joined:
+		return planNative
+	case !limited && caps.MutationJoin == JoinMutationInline:
+		return planInlineJoin
+	case !limited && caps.MutationJoin == JoinMutationFrom:
+		return planFromJoin
+	case !joined && caps.MutationLimit && q.offset == 0:
+		return planNative
+	case caps.RowID != "":
+		return planRowSubquery
+	}
+	return planUnsupported
+}
+
+// Create mutation query which joins tables with FROM or USING clause,
+// prefix is "UPDATE t SET ..." or "DELETE FROM t", and keyword is "FROM" or "USING".
+func (q *QueryBuilder) buildFromJoinMutation(prefix, keyword string, target interface{}, wheres []ConditionBuilder, binds []interface{}) (string, []interface{}, error) {
+	tables, wheres := q.buildJoinFrom(target, wheres)
+	where, binds := buildWhere(q.compat, wheres, binds)
+	return prefix + " " + keyword + " " + tables + where, binds, nil
+}
+
+// Create mutation query which selects target rows by row identifier subquery
+func (q *QueryBuilder) buildRowSubqueryMutation(prefix, rowID, mainTable string, target interface{}, wheres []ConditionBuilder, binds []interface{}) (string, []interface{}, error) {
+	where, binds, err := q.buildRowSubquery(rowID, mainTable, target, wheres, binds)
+	if err != nil {
+		return "", nil, err
+	}
+	return prefix + where, binds, nil
+}
+
+// Check mutation query needs subquery to emulate orders, limit and offset
+func (q *QueryBuilder) needsRowSubquery() bool {
+	return len(q.orders) > 0 || q.limit > 0 || q.offset > 0
+}
+
+// Create WHERE clause which selects target rows by row identifier like ctid on PostgreSQL or rowid on SQLite.
+// Joins, conditions, orders, limit and offset are applied in subquery.
+func (q *QueryBuilder) buildRowSubquery(rowID, mainTable string, target interface{}, wheres []ConditionBuilder, binds []interface{}) (string, []interface{}, error) {
+	limit, err := q.buildLimitOffset()
+	if err != nil {
+		return "", nil, err
+	}
+	where, binds := buildWhere(q.compat, wheres, binds)
+	return fmt.Sprintf(
+		" WHERE %s IN (SELECT %s.%s FROM %s%s%s%s%s)",
+		rowID,
+		quote(q.compat, target),
+		rowID,
+		mainTable,
+		buildJoin(q.compat, q.joins, target),
+		where,
+		buildOrderBy(q.compat, q.orders),
+		limit,
+	), binds, nil
+}
+
+// Create table list for FROM or USING clause of PostgreSQL, and add join conditions to conditions
+func (q *QueryBuilder) buildJoinFrom(target interface{}, wheres []ConditionBuilder) (string, []ConditionBuilder) {
+	if len(q.joins) == 0 {
+		return "", wheres
+	}
+	tables := []string{}
+	for _, j := range q.joins {
+		tables = append(tables, buildJoinTable(q.compat, j.table))
+		wheres = restrictConditions(wheres, rawCondition{
+			expr:    newRawExpr(buildJoinCondition(q.compat, j, target), nil),
+			combine: And,
+		})
+	}
+	return strings.Join(tables, ", "), wheres
+}
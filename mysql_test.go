@@ -1,6 +1,13 @@
 package gqb_test
 
 import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/base64"
+	"io"
+	"io/ioutil"
+	"strings"
 	"testing"
 	"time"
 
@@ -305,6 +312,17 @@ func runMysqlTest(t *testing.T) {
 		}
 	})
 
+	t.Run("BulkInsert() returns error for data without columns", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).BulkInsert("example", []gqb.Data{{}})
+		assert.EqualError(t, err, "insert data must have at least one column")
+		assert.Equal(t, "", m.query)
+
+		_, err = gqb.InsertLoader{}.Load(context.Background(), m, "example", nil, nil)
+		assert.EqualError(t, err, "columns must not be empty")
+		assert.Equal(t, "", m.query)
+	})
+
 	t.Run("BulkInsert query", func(t *testing.T) {
 		m := &mockExecutor{}
 		_, err := gqb.New(m).
@@ -380,4 +398,691 @@ func runMysqlTest(t *testing.T) {
 			assert.Equal(t, 1, v)
 		}
 	})
+
+	t.Run("WhereRaw() with bind parameters", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			Where("id", 1, gqb.Equal).
+			WhereRaw("lower(email) = ? OR name LIKE '?%'", "john@example.com").
+			Get("example")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, "SELECT * FROM `example` WHERE (`id` = ?) AND (lower(email) = ? OR name LIKE '?%')", m.query)
+		assert.Equal(t, []interface{}{1, "john@example.com"}, m.binds)
+	})
+
+	t.Run("WhereRaw() ignores quotes and placeholders in comments", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			WhereRaw("id = ? -- what's up?\n AND /* isn't it? */ name = ?", 1, "John").
+			Get("example")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, "SELECT * FROM `example` WHERE (id = ? -- what's up?\n AND /* isn't it? */ name = ?)", m.query)
+		assert.Equal(t, []interface{}{1, "John"}, m.binds)
+
+		_, err = gqb.New(m).
+			WhereRaw("id = ? /* unterminated", 1).
+			Get("example")
+		assert.Error(t, err)
+	})
+
+	t.Run("Select() contains raw field with bind parameters", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			Select("id", gqb.Raw("COALESCE(name, ?) AS name").Bind("unknown")).
+			Where("id", 1, gqb.Equal).
+			Get("example")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, "SELECT `id`, COALESCE(name, ?) AS name FROM `example` WHERE (`id` = ?)", m.query)
+		assert.Equal(t, []interface{}{"unknown", 1}, m.binds)
+	})
+
+	t.Run("WhereRaw() returns error if bind parameters are short", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			WhereGroup(func(g *gqb.WhereGroup) {
+				g.WhereRaw("id = ? OR id = ?", 1)
+			}).
+			Get("example")
+		assert.Error(t, err)
+		assert.Equal(t, "", m.query)
+	})
+
+	t.Run("WhereRaw() returns error if bind parameters are extra", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			WhereRaw("id = ?", 1, 2).
+			Get("example")
+		assert.EqualError(t, err, "raw phrase has fewer placeholders than bind parameters: id = ?")
+		assert.Equal(t, "", m.query)
+
+		var expr gqb.RawExpr = gqb.Raw("COALESCE(name, $2)").Bind("unused", "unknown")
+		_, err = gqb.New(m).
+			Select(expr).
+			Get("example")
+		assert.Error(t, err)
+		assert.Equal(t, "", m.query)
+	})
+
+	t.Run("WhereRaw() with named parameters", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			Where("id", 1, gqb.Equal).
+			WhereRaw("lower(email) = :email OR lower(alias) = @email OR @@sql_mode = ':email'", gqb.Params{
+				"email": "john@example.com",
+			}).
+			Get("example")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, "SELECT * FROM `example` WHERE (`id` = ?) AND (lower(email) = ? OR lower(alias) = ? OR @@sql_mode = ':email')", m.query)
+		assert.Equal(t, []interface{}{1, "john@example.com", "john@example.com"}, m.binds)
+	})
+
+	t.Run("RawQuery() rewrites named parameters", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			RawQuery(context.Background(), "SELECT * FROM example WHERE id = :id OR parent_id = :id", gqb.Params{
+				"id": 10,
+			})
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, "SELECT * FROM example WHERE id = ? OR parent_id = ?", m.query)
+		assert.Equal(t, []interface{}{10, 10}, m.binds)
+	})
+
+	t.Run("RawQuery() returns error if named parameter is missing", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			RawQuery(context.Background(), "SELECT * FROM example WHERE id = :id", gqb.Params{})
+		assert.Error(t, err)
+		assert.Equal(t, "", m.query)
+	})
+
+	t.Run("Exec() without bind parameters outputs query as it is", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			Exec(context.Background(), "DELETE FROM example WHERE name = '?'")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, "DELETE FROM example WHERE name = '?'", m.query)
+		assert.Equal(t, 0, len(m.binds))
+	})
+
+	t.Run("Paginate() first page query", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			Where("status", 1, gqb.Equal).
+			Paginate(context.Background(), "example", gqb.Cursor{
+				OrderBy: []gqb.Order{gqb.NewOrder("created_at", gqb.Desc), gqb.NewOrder("id", gqb.Desc)},
+				Size:    50,
+			})
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, "SELECT * FROM `example` WHERE (`status` = ?) ORDER BY `created_at` DESC, `id` DESC LIMIT 51", m.query)
+		assert.Equal(t, []interface{}{1}, m.binds)
+	})
+
+	t.Run("Paginate() with after cursor uses row value comparison", func(t *testing.T) {
+		m := &mockExecutor{}
+		token := base64.RawURLEncoding.EncodeToString([]byte(`["2018-01-01 00:00:00",10]`))
+		_, err := gqb.New(m).
+			Where("status", 1, gqb.Equal).
+			OrWhere("status", 2, gqb.Equal).
+			Paginate(context.Background(), "example", gqb.Cursor{
+				OrderBy: []gqb.Order{gqb.NewOrder("created_at", gqb.Desc), gqb.NewOrder("id", gqb.Desc)},
+				After:   token,
+				Size:    50,
+			})
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, "SELECT * FROM `example` WHERE ((`status` = ?) OR (`status` = ?)) AND ((`created_at`, `id`) < (?, ?)) ORDER BY `created_at` DESC, `id` DESC LIMIT 51", m.query)
+		assert.Equal(t, []interface{}{1, 2, "2018-01-01 00:00:00", int64(10)}, m.binds)
+	})
+
+	t.Run("Paginate() keeps sub-second precision and time zone of time cursor", func(t *testing.T) {
+		jst := time.FixedZone("JST", 9*60*60)
+		created := time.Date(2018, 1, 2, 3, 4, 5, 123456789, jst)
+		db, _ := openRows(t, rowsResult{
+			columns: []string{"created_at", "id"},
+			values: [][]driver.Value{
+				{created, int64(11)},
+				{created, int64(10)},
+			},
+		})
+		orders := []gqb.Order{gqb.NewOrder("created_at", gqb.Desc), gqb.NewOrder("id", gqb.Desc)}
+		page, err := gqb.New(db).
+			Paginate(context.Background(), "example", gqb.Cursor{OrderBy: orders, Size: 1})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(page.Items))
+		assert.NotEqual(t, "", page.Next)
+
+		m := &mockExecutor{}
+		_, err = gqb.New(m).
+			Paginate(context.Background(), "example", gqb.Cursor{OrderBy: orders, After: page.Next, Size: 1})
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, "SELECT * FROM `example` WHERE ((`created_at`, `id`) < (?, ?)) ORDER BY `created_at` DESC, `id` DESC LIMIT 2", m.query)
+		assert.Equal(t, []interface{}{"2018-01-02 03:04:05.123456789", int64(11)}, m.binds)
+	})
+
+	t.Run("Paginate() returns error on invalid cursor", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			Paginate(context.Background(), "example", gqb.Cursor{
+				OrderBy: []gqb.Order{gqb.NewOrder("id", gqb.Asc)},
+				After:   "invalid token",
+				Size:    50,
+			})
+		assert.Error(t, err)
+		assert.Equal(t, "", m.query)
+	})
+
+	t.Run("Page() executes count query with conditions", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			Select("id", "name").
+			Join("users", "id", "id", gqb.Equal).
+			Where("status", 1, gqb.Equal).
+			OrderBy("id", gqb.Desc).
+			Page(context.Background(), "example", 2, 20)
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, "SELECT COUNT(*) AS `total` FROM `example` JOIN `users` ON (`example`.`id` = `users`.`id`) WHERE (`status` = ?)", m.query)
+		assert.Equal(t, []interface{}{1}, m.binds)
+	})
+
+	t.Run("Page() executes items query with limit and offset", func(t *testing.T) {
+		db, c := openRows(t,
+			rowsResult{columns: []string{"total"}, values: [][]driver.Value{{int64(45)}}},
+			rowsResult{columns: []string{"id", "name"}, values: [][]driver.Value{{int64(21), "Google"}, {int64(22), "Apple"}}},
+		)
+		page, err := gqb.New(db).
+			Select("id", "name").
+			Where("status", 1, gqb.Equal).
+			OrderBy("id", gqb.Asc).
+			Page(context.Background(), "example", 2, 20)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{
+			"SELECT COUNT(*) AS `total` FROM `example` WHERE (`status` = ?)",
+			"SELECT `id`, `name` FROM `example` WHERE (`status` = ?) ORDER BY `id` ASC LIMIT 20 OFFSET 20",
+		}, c.queries)
+		assert.Equal(t, [][]driver.Value{{int64(1)}, {int64(1)}}, c.binds)
+		assert.Equal(t, int64(45), page.Total)
+		assert.Equal(t, int64(2), page.Page)
+		assert.Equal(t, int64(20), page.PerPage)
+		assert.Equal(t, int64(3), page.LastPage)
+		assert.Equal(t, 2, len(page.Items))
+		assert.Equal(t, "Google", page.Items[0].MustString("name"))
+	})
+
+	t.Run("Page() calculates last page when total is divisible or zero", func(t *testing.T) {
+		db, _ := openRows(t,
+			rowsResult{columns: []string{"total"}, values: [][]driver.Value{{int64(40)}}},
+			rowsResult{columns: []string{"id"}},
+			rowsResult{columns: []string{"total"}, values: [][]driver.Value{{int64(0)}}},
+			rowsResult{columns: []string{"id"}},
+		)
+		page, err := gqb.New(db).Page(context.Background(), "example", 1, 20)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(40), page.Total)
+		assert.Equal(t, int64(2), page.LastPage)
+
+		page, err = gqb.New(db).Page(context.Background(), "example", 1, 20)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), page.Total)
+		assert.Equal(t, int64(1), page.LastPage)
+		assert.Equal(t, 0, len(page.Items))
+	})
+
+	t.Run("Scopes() applies reusable query fragments", func(t *testing.T) {
+		m := &mockExecutor{}
+		active := func(q *gqb.QueryBuilder) *gqb.QueryBuilder {
+			return q.Where("deleted_at", nil, gqb.Equal)
+		}
+		tenant := func(id int) gqb.Scope {
+			return func(q *gqb.QueryBuilder) *gqb.QueryBuilder {
+				return q.Join("tenants", "tenant_id", "id", gqb.Equal).Where("tenants.id", id, gqb.Equal)
+			}
+		}
+		recent := func(q *gqb.QueryBuilder) *gqb.QueryBuilder {
+			return q.OrderBy("created_at", gqb.Desc)
+		}
+		published := func(g *gqb.WhereGroup) *gqb.WhereGroup {
+			return g.Where("status", "published", gqb.Equal).OrWhere("status", "featured", gqb.Equal)
+		}
+		_, err := gqb.New(m).
+			Scopes(active, tenant(10), recent).
+			WhereGroup(func(g *gqb.WhereGroup) {
+				g.Scopes(published)
+			}).
+			Get("posts")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, "SELECT * FROM `posts` JOIN `tenants` ON (`posts`.`tenant_id` = `tenants`.`id`) WHERE (`deleted_at` IS NULL) AND (`tenants`.`id` = ?) AND (`status` = ? OR `status` = ?) ORDER BY `created_at` DESC", m.query)
+		assert.Equal(t, []interface{}{10, "published", "featured"}, m.binds)
+	})
+
+	t.Run("Timestamps() stamps created_at and updated_at on Insert", func(t *testing.T) {
+		m := &mockExecutor{}
+		now := time.Date(2018, 1, 2, 3, 4, 5, 123456789, time.UTC)
+		_, err := gqb.New(m, gqb.Timestamps(gqb.Timestamp{}), gqb.Clock(func() time.Time { return now })).
+			Insert("example", gqb.Data{
+				"name": "John Smith",
+			})
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, "INSERT INTO `example` (`created_at`, `name`, `updated_at`) VALUES (?, ?, ?)", m.query)
+		assert.Equal(t, []interface{}{"2018-01-02 03:04:05", "John Smith", "2018-01-02 03:04:05"}, m.binds)
+	})
+
+	t.Run("Timestamps() stamps updated_at on Update with precision", func(t *testing.T) {
+		m := &mockExecutor{}
+		now := time.Date(2018, 1, 2, 3, 4, 5, 123456789, time.UTC)
+		_, err := gqb.New(m, gqb.Timestamps(gqb.Timestamp{UpdatedAt: "modified", Precision: time.Millisecond}), gqb.Clock(func() time.Time { return now })).
+			Where("id", 1, gqb.Equal).
+			Update("example", gqb.Data{
+				"name": "John Smith",
+			})
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, "UPDATE `example` SET `modified` = ?, `name` = ? WHERE (`id` = ?)", m.query)
+		assert.Equal(t, []interface{}{"2018-01-02 03:04:05.123", "John Smith", 1}, m.binds)
+	})
+
+	t.Run("Timestamps() works with struct-driven BulkInsert", func(t *testing.T) {
+		type Company struct {
+			Name      string     `db:"name"`
+			URL       *string    `db:"url"`
+			CreatedAt *time.Time `db:"created_at"`
+			Note      string
+		}
+		now := time.Date(2018, 1, 2, 3, 4, 5, 0, time.UTC)
+		created := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+		d1, err := gqb.NewData(Company{Name: "Google"})
+		assert.NoError(t, err)
+		d2, err := gqb.NewData(&Company{Name: "Apple", CreatedAt: &created})
+		assert.NoError(t, err)
+
+		m := &mockExecutor{}
+		_, err = gqb.New(m, gqb.Timestamps(gqb.Timestamp{}), gqb.Clock(func() time.Time { return now })).
+			BulkInsert("companies", []gqb.Data{d1, d2})
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, "INSERT INTO `companies` (`created_at`, `name`, `updated_at`, `url`) VALUES (?, ?, ?, ?), (?, ?, ?, ?)", m.query)
+		assert.Equal(t, []interface{}{
+			"2018-01-02 03:04:05", "Google", "2018-01-02 03:04:05", nil,
+			"2017-01-01 00:00:00", "Apple", "2018-01-02 03:04:05", nil,
+		}, m.binds)
+	})
+
+	t.Run("Timestamps() treats zero time of struct field as unset", func(t *testing.T) {
+		type Company struct {
+			Name      string    `db:"name"`
+			CreatedAt time.Time `db:"created_at"`
+			UpdatedAt time.Time `db:"updated_at"`
+		}
+		now := time.Date(2018, 1, 2, 3, 4, 5, 0, time.UTC)
+		d, err := gqb.NewData(Company{Name: "Google"})
+		assert.NoError(t, err)
+
+		m := &mockExecutor{}
+		_, err = gqb.New(m, gqb.Timestamps(gqb.Timestamp{}), gqb.Clock(func() time.Time { return now })).
+			Insert("companies", d)
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, "INSERT INTO `companies` (`created_at`, `name`, `updated_at`) VALUES (?, ?, ?)", m.query)
+		assert.Equal(t, []interface{}{"2018-01-02 03:04:05", "Google", "2018-01-02 03:04:05"}, m.binds)
+
+		m = &mockExecutor{}
+		_, err = gqb.New(m, gqb.Timestamps(gqb.Timestamp{}), gqb.Clock(func() time.Time { return now })).
+			Where("id", 1, gqb.Equal).
+			UpdateStruct("companies", Company{Name: "Google"})
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, "UPDATE `companies` SET `name` = ?, `updated_at` = ? WHERE (`id` = ?)", m.query)
+		assert.Equal(t, []interface{}{"Google", "2018-01-02 03:04:05", 1}, m.binds)
+	})
+
+	t.Run("NewData() skips unexported fields", func(t *testing.T) {
+		type Company struct {
+			Name   string `db:"name"`
+			secret string `db:"secret"`
+		}
+		d, err := gqb.NewData(Company{Name: "Google", secret: "password"})
+		assert.NoError(t, err)
+		assert.Equal(t, gqb.Data{"name": "Google"}, d)
+
+		_, err = gqb.NewData(10)
+		assert.EqualError(t, err, "source value must be a struct: int")
+	})
+
+	t.Run("Update query binds Raw value as it is", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			Where("id", 1, gqb.Equal).
+			Update("example", gqb.Data{
+				"name": gqb.Raw("name'); DROP TABLE example; --"),
+			})
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, "UPDATE `example` SET `name` = ? WHERE (`id` = ?)", m.query)
+		assert.Equal(t, []interface{}{gqb.Raw("name'); DROP TABLE example; --"), 1}, m.binds)
+	})
+
+	t.Run("OptimisticLock() compares and increments version", func(t *testing.T) {
+		type Company struct {
+			Name    string `db:"name"`
+			Version int    `db:"version"`
+		}
+		m := &affectedExecutor{affected: 1}
+		_, err := gqb.New(m, gqb.OptimisticLock("version")).
+			Where("id", 1, gqb.Equal).
+			UpdateStruct("companies", Company{Name: "Google", Version: 3})
+		assert.NoError(t, err)
+		assert.Equal(t, "UPDATE `companies` SET `name` = ?, `version` = `version` + 1 WHERE (`id` = ?) AND (`version` = ?)", m.query)
+		assert.Equal(t, []interface{}{"Google", 1, 3}, m.binds)
+	})
+
+	t.Run("OptimisticLock() returns ErrStaleObject when no rows are updated", func(t *testing.T) {
+		m := &affectedExecutor{affected: 0}
+		_, err := gqb.New(m, gqb.OptimisticLock("version")).
+			Where("id", 1, gqb.Equal).
+			Update("companies", gqb.Data{"name": "Google", "version": 3})
+		assert.Equal(t, gqb.ErrStaleObject, err)
+	})
+
+	t.Run("OptimisticLock() returns error without version value", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m, gqb.OptimisticLock("version")).
+			Where("id", 1, gqb.Equal).
+			Update("companies", gqb.Data{"name": "Google"})
+		assert.Error(t, err)
+		assert.Equal(t, "", m.query)
+	})
+
+	t.Run("BulkLoad() with LOAD DATA streams rows through reader handler", func(t *testing.T) {
+		m := &readerExecutor{handlers: map[string]func() io.Reader{}}
+		loader := gqb.MysqlLoadDataLoader{
+			Register: func(name string, handler func() io.Reader) {
+				m.handlers[name] = handler
+			},
+			Deregister: func(name string) {
+				delete(m.handlers, name)
+			},
+		}
+		loaded, err := gqb.New(m, gqb.BulkLoader(loader)).
+			BulkLoad(context.Background(), "example", []string{"id", "name"}, gqb.RowsFromSlice([][]interface{}{
+				{1, "John\tSmith"},
+				{2, nil},
+			}))
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), loaded)
+		assert.Regexp(t, "^LOAD DATA LOCAL INFILE 'Reader::gqb_[0-9]+' INTO TABLE `example` .+ \\(`id`, `name`\\)$", m.query)
+		assert.Equal(t, "1\tJohn\\tSmith\n2\t\\N\n", m.content)
+		assert.Equal(t, 0, len(m.handlers))
+	})
+
+	t.Run("BulkUpdate query uses CASE expression", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			BulkUpdate("example", "id", []gqb.Data{
+				gqb.Data{"id": 1, "name": "John Smith", "age": 20},
+				gqb.Data{"id": 2, "name": "Jane Smith", "age": 30},
+			})
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, "UPDATE `example` SET `age` = CASE `id` WHEN ? THEN ? WHEN ? THEN ? END, `name` = CASE `id` WHEN ? THEN ? WHEN ? THEN ? END WHERE (`id` IN (?, ?))", m.query)
+		assert.Equal(t, []interface{}{1, 20, 2, 30, 1, "John Smith", 2, "Jane Smith", 1, 2}, m.binds)
+	})
+
+	t.Run("BulkUpdate returns error if data doesn't have key column", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			BulkUpdate("example", "id", []gqb.Data{
+				gqb.Data{"name": "John Smith"},
+			})
+		assert.Error(t, err)
+		assert.Equal(t, "", m.query)
+	})
+
+	t.Run("Update query with JOIN", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			Join("users", "user_id", "id", gqb.Equal).
+			Where("users.active", 0, gqb.Equal).
+			Update("example", gqb.Data{"name": "Jane Smith"})
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, "UPDATE `example` JOIN `users` ON (`example`.`user_id` = `users`.`id`) SET `name` = ? WHERE (`users`.`active` = ?)", m.query)
+		assert.Equal(t, []interface{}{"Jane Smith", 0}, m.binds)
+	})
+
+	t.Run("Delete query with ORDER BY and LIMIT", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			Where("active", 0, gqb.Equal).
+			OrderBy("id", gqb.Asc).
+			Limit(10).
+			Delete("example")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, "DELETE FROM `example` WHERE (`active` = ?) ORDER BY `id` ASC LIMIT 10", m.query)
+		assert.Equal(t, []interface{}{0}, m.binds)
+	})
+
+	t.Run("Delete query with JOIN", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			Join("users", "user_id", "id", gqb.Equal).
+			Where("users.active", 0, gqb.Equal).
+			Delete(gqb.Alias("example", "e"))
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, "DELETE `e` FROM `example` AS `e` JOIN `users` ON (`e`.`user_id` = `users`.`id`) WHERE (`users`.`active` = ?)", m.query)
+	})
+
+	t.Run("Update query with JOIN and LIMIT returns error", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			Join("users", "user_id", "id", gqb.Equal).
+			Where("users.active", 0, gqb.Equal).
+			Limit(10).
+			Update("example", gqb.Data{"name": "Jane Smith"})
+		assert.Error(t, err)
+		assert.Equal(t, "", m.query)
+	})
+
+	t.Run("InsertReturning() returns error", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			InsertReturning(context.Background(), "example", gqb.Data{"name": "John Smith"})
+		assert.Error(t, err)
+		assert.Equal(t, "", m.query)
+	})
+
+	t.Run("Final() returns error on MySQL", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			Final().
+			Get("example")
+		assert.Error(t, err)
+		assert.Equal(t, "", m.query)
+	})
+
+	t.Run("Upsert() uses ON DUPLICATE KEY UPDATE", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			Upsert("example", gqb.Data{"id": 1, "name": "John Smith"}, "id")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, "INSERT INTO `example` (`id`, `name`) VALUES (?, ?) ON DUPLICATE KEY UPDATE `name` = VALUES(`name`)", m.query)
+		assert.Equal(t, []interface{}{1, "John Smith"}, m.binds)
+	})
+
+	t.Run("Upsert() returns error under tenant policy", func(t *testing.T) {
+		m := &mockExecutor{}
+		ctx := gqb.WithTenant(context.Background(), 10)
+		_, err := gqb.New(m, gqb.Tenant(gqb.TenantPolicy{})).
+			UpsertContext(ctx, "example", gqb.Data{"email": "john@example.com", "name": "John Smith"}, "email")
+		assert.Error(t, err)
+		assert.Equal(t, "", m.query)
+	})
+
+	t.Run("Upsert() returns error for empty data", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			Upsert("example", gqb.Data{}, "id")
+		assert.Error(t, err)
+		assert.Equal(t, "", m.query)
+	})
+
+	t.Run("Quote() escapes embedded backtick", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			Where("na`me", "John", gqb.Equal).
+			OrderBy("id` DESC; DROP TABLE users; --", gqb.Asc).
+			Get("example")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, "SELECT * FROM `example` WHERE (`na``me` = ?) ORDER BY `id`` DESC; DROP TABLE users; --` ASC", m.query)
+	})
+
+	t.Run("Quote() keeps already quoted identifier", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			Select("`example`.`id`").
+			Get("example")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, "SELECT `example`.`id` FROM `example`", m.query)
+	})
+
+	t.Run("StrictIdentifiers() rejects invalid identifiers", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m, gqb.StrictIdentifiers()).
+			Where("users.name", "John", gqb.Equal).
+			OrderBy("id; DROP TABLE users", gqb.Asc).
+			Get("users")
+		assert.EqualError(t, err, `Identifier "id; DROP TABLE users" is not allowed in strict mode`)
+		assert.Equal(t, "", m.query)
+
+		_, err = gqb.New(m, gqb.StrictIdentifiers()).
+			WhereGroup(func(g *gqb.WhereGroup) {
+				g.Where("na`me", "John", gqb.Equal)
+			}).
+			Get("users")
+		assert.Error(t, err)
+
+		_, err = gqb.New(m, gqb.StrictIdentifiers()).
+			Insert("users", gqb.Data{"name) VALUES (1); --": "John"})
+		assert.Error(t, err)
+		assert.Equal(t, "", m.query)
+	})
+
+	t.Run("StrictIdentifiers() accepts valid identifiers", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m, gqb.StrictIdentifiers()).
+			Select("users.id", gqb.Raw("COUNT(*) AS total")).
+			Join("companies", "company_id", "id", gqb.Equal).
+			Where("users.name", "John", gqb.Equal).
+			GroupBy("users.id").
+			Get("users")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, "SELECT `users`.`id`, COUNT(*) AS total FROM `users` JOIN `companies` ON (`users`.`company_id` = `companies`.`id`) WHERE (`users`.`name` = ?) GROUP BY `users`.`id`", m.query)
+	})
+
+	t.Run("SafeOrderBy() sorts by allowed field", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			Scopes(
+				gqb.SafeOrderBy("-created_at", "id", "created_at"),
+				gqb.SafeOrderBy("id", "id", "created_at"),
+				gqb.SafeOrderBy("", "id"),
+			).
+			Get("users")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, "SELECT * FROM `users` ORDER BY `created_at` DESC, `id` ASC", m.query)
+	})
+
+	t.Run("SafeOrderBy() returns error for not allowed field", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			Scopes(gqb.SafeOrderBy("-password", "id", "created_at")).
+			Get("users")
+		assert.EqualError(t, err, `Sort field "-password" is not allowed`)
+		assert.Equal(t, "", m.query)
+	})
+
+	t.Run("Join() accepts aliased table", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			Join(gqb.Alias("posts", "p"), "id", "user_id", gqb.Equal).
+			Where("p.published", 1, gqb.Equal).
+			Get("users")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, "SELECT * FROM `users` JOIN `posts` AS `p` ON (`users`.`id` = `p`.`user_id`) WHERE (`p`.`published` = ?)", m.query)
+	})
+
+	t.Run("Invalid identifier types return error", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).OrderBy(1, gqb.Asc).Get("users")
+		assert.Error(t, err)
+		_, err = gqb.New(m).GroupBy(1).Get("users")
+		assert.Error(t, err)
+		_, err = gqb.New(m).Where(1, 1, gqb.Equal).Get("users")
+		assert.Error(t, err)
+		_, err = gqb.New(m).WhereGroup(func(g *gqb.WhereGroup) {
+			g.Where(1, 1, gqb.Equal)
+		}).Get("users")
+		assert.Error(t, err)
+		_, err = gqb.New(m).Join(gqb.Alias(1, "p"), "id", "user_id", gqb.Equal).Get("users")
+		assert.Error(t, err)
+		_, err = gqb.New(m).Join("posts", 1, "user_id", gqb.Equal).Get("users")
+		assert.Error(t, err)
+		_, err = gqb.New(m).Get(gqb.Alias(1, "u"))
+		assert.Error(t, err)
+		_, err = gqb.New(m).Paginate(context.Background(), "users", gqb.Cursor{
+			OrderBy: []gqb.Order{gqb.NewOrder(1, gqb.Asc)},
+			Size:    10,
+		})
+		assert.Error(t, err)
+		assert.Equal(t, "", m.query)
+	})
+
+	t.Run("Ident escapes embedded backtick in each part", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			Update(gqb.Ident{"db.v2", "users"}, gqb.Data{"na`me": "John"})
+		assert.Error(t, err)
+		assert.Equal(t, "", m.query)
+
+		_, err = gqb.New(m).
+			Where("id", 1, gqb.Equal).
+			Update(gqb.Ident{"db.v2", "users"}, gqb.Data{"na`me": "John"})
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, "UPDATE `db.v2`.`users` SET `na``me` = ? WHERE (`id` = ?)", m.query)
+	})
+
+	t.Run("StrictIdentifiers() checks each part of Ident", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m, gqb.StrictIdentifiers()).
+			Get(gqb.Ident{"app", "users"})
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, "SELECT * FROM `app`.`users`", m.query)
+
+		_, err = gqb.New(m, gqb.StrictIdentifiers()).
+			Get(gqb.Ident{"app", "users; --"})
+		assert.EqualError(t, err, `Identifier "users; --" is not allowed in strict mode`)
+
+		_, err = gqb.New(m, gqb.StrictIdentifiers()).
+			Where(gqb.Ident{"users", "first.name"}, "John", gqb.Equal).
+			Get("users")
+		assert.EqualError(t, err, `Identifier "first.name" is not allowed in strict mode`)
+	})
+
+	t.Run("OrderBy() with random doesn't sort by field", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			OrderBy("id", gqb.Rand).
+			Get("example")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, "SELECT * FROM `example` ORDER BY RAND()", m.query)
+	})
+}
+
+// readerExecutor reads content from registered reader handler on LOAD DATA query like MySQL driver
+type readerExecutor struct {
+	mockExecutor
+	handlers map[string]func() io.Reader
+	content  string
+}
+
+func (r *readerExecutor) ExecContext(ctx context.Context, query string, binds ...interface{}) (sql.Result, error) {
+	r.query = query
+	for _, handler := range r.handlers {
+		buf, err := ioutil.ReadAll(handler())
+		if err != nil {
+			return nil, err
+		}
+		r.content = string(buf)
+	}
+	return affectedResult(strings.Count(r.content, "\n")), nil
 }
@@ -0,0 +1,214 @@
+package gqb_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ysugimoto/gqb"
+)
+
+func runOracleTest(t *testing.T) {
+	gqb.SetDriver("oracle")
+
+	t.Run("Table aliasing", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			Get(gqb.Alias("example", "e"))
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, `SELECT * FROM "EXAMPLE" "E"`, m.query)
+	})
+
+	t.Run("Where() with placeholders", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			Select("example.id", "name").
+			Where("id", 1, gqb.Equal).
+			Where("name", "John", gqb.Equal).
+			Get("example")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, `SELECT "EXAMPLE"."ID", "NAME" FROM "EXAMPLE" WHERE ("ID" = :1) AND ("NAME" = :2)`, m.query)
+		assert.Equal(t, []interface{}{1, "John"}, m.binds)
+	})
+
+	t.Run("Limit() uses FETCH FIRST", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			OrderBy("id", gqb.Desc).
+			Limit(10).
+			Get("example")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, `SELECT * FROM "EXAMPLE" ORDER BY "ID" DESC FETCH FIRST 10 ROWS ONLY`, m.query)
+	})
+
+	t.Run("Limit() and Offset() use OFFSET FETCH", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			OrderBy("id", gqb.Desc).
+			Limit(10).
+			Offset(20).
+			Get("example")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, `SELECT * FROM "EXAMPLE" ORDER BY "ID" DESC OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY`, m.query)
+	})
+
+	t.Run("OrderBy() with random", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			OrderBy("id", gqb.Rand).
+			Get("example")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, `SELECT * FROM "EXAMPLE" ORDER BY DBMS_RANDOM.VALUE`, m.query)
+	})
+
+	t.Run("Time value is bound as it is", func(t *testing.T) {
+		m := &mockExecutor{}
+		now := time.Date(2018, 1, 2, 3, 4, 5, 0, time.UTC)
+		_, err := gqb.New(m).
+			Where("created_at", now, gqb.Lt).
+			Get("example")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, `SELECT * FROM "EXAMPLE" WHERE ("CREATED_AT" < :1)`, m.query)
+		assert.Equal(t, []interface{}{now}, m.binds)
+	})
+
+	t.Run("InsertReturningInto() uses RETURNING INTO with out parameters", func(t *testing.T) {
+		m := &mockExecutor{}
+		var id int64
+		err := gqb.New(m).
+			InsertReturningInto(context.Background(), "example", gqb.Data{"name": "John Smith"}, gqb.Params{"id": &id})
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, `INSERT INTO "EXAMPLE" ("NAME") VALUES (:1) RETURNING "ID" INTO :2`, m.query)
+		assert.Equal(t, []interface{}{"John Smith", sql.Out{Dest: &id}}, m.binds)
+	})
+
+	t.Run("InsertReturning() returns error", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			InsertReturning(context.Background(), "example", gqb.Data{"name": "John Smith"})
+		assert.Error(t, err)
+		assert.Equal(t, "", m.query)
+	})
+
+	t.Run("Page() reads total count of quoted lower case alias", func(t *testing.T) {
+		db, c := openRows(t,
+			rowsResult{columns: []string{"total"}, values: [][]driver.Value{{int64(3)}}},
+			rowsResult{columns: []string{"ID", "NAME"}, values: [][]driver.Value{{int64(1), "John"}, {int64(2), "Jane"}}},
+		)
+		page, err := gqb.New(db).
+			Where("active", 1, gqb.Equal).
+			Page(context.Background(), "example", 1, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{
+			`SELECT COUNT(*) AS "total" FROM "EXAMPLE" WHERE ("ACTIVE" = :1)`,
+			`SELECT * FROM "EXAMPLE" WHERE ("ACTIVE" = :1) FETCH FIRST 2 ROWS ONLY`,
+		}, c.queries)
+		assert.Equal(t, int64(3), page.Total)
+		assert.Equal(t, int64(2), page.LastPage)
+		assert.Equal(t, 2, len(page.Items))
+	})
+
+	t.Run("Paginate() reads cursor fields of upper case columns", func(t *testing.T) {
+		db, _ := openRows(t, rowsResult{
+			columns: []string{"ID", "NAME"},
+			values: [][]driver.Value{
+				{int64(10), "John"},
+				{int64(11), "Jane"},
+			},
+		})
+		orders := []gqb.Order{gqb.NewOrder("id", gqb.Asc)}
+		page, err := gqb.New(db).
+			Paginate(context.Background(), "example", gqb.Cursor{OrderBy: orders, Size: 1})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(page.Items))
+		assert.NotEqual(t, "", page.Next)
+
+		m := &mockExecutor{}
+		_, err = gqb.New(m).
+			Paginate(context.Background(), "example", gqb.Cursor{OrderBy: orders, After: page.Next, Size: 1})
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, `SELECT * FROM "EXAMPLE" WHERE (("ID" > :1)) ORDER BY "ID" ASC FETCH FIRST 2 ROWS ONLY`, m.query)
+		assert.Equal(t, []interface{}{int64(10)}, m.binds)
+	})
+
+	t.Run("BulkInsert query uses INSERT ALL", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			BulkInsert("example", []gqb.Data{
+				{"id": 1, "name": "John"},
+				{"id": 2, "name": "Jane"},
+			})
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, `INSERT ALL INTO "EXAMPLE" ("ID", "NAME") VALUES (:1, :2) INTO "EXAMPLE" ("ID", "NAME") VALUES (:3, :4) SELECT 1 FROM dual`, m.query)
+		assert.Equal(t, []interface{}{1, "John", 2, "Jane"}, m.binds)
+	})
+
+	t.Run("BulkUpdate query uses CASE expression", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			BulkUpdate("example", "id", []gqb.Data{
+				{"id": 1, "name": "John"},
+				{"id": 2, "name": "Jane"},
+			})
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, `UPDATE "EXAMPLE" SET "NAME" = CASE "ID" WHEN :1 THEN :2 WHEN :3 THEN :4 END WHERE ("ID" IN (:5, :6))`, m.query)
+	})
+
+	t.Run("Lock() appends locking clause", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			Where("id", 1, gqb.Equal).
+			Lock(gqb.LockForUpdate).
+			Get("example")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, `SELECT * FROM "EXAMPLE" WHERE ("ID" = :1) FOR UPDATE`, m.query)
+	})
+
+	t.Run("Lock() with Limit() returns error", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			OrderBy("id", gqb.Asc).
+			Limit(10).
+			Lock(gqb.LockForUpdate).
+			Get("example")
+		assert.Error(t, err)
+		assert.Equal(t, "", m.query)
+	})
+
+	gqb.SetDriver("oracle11")
+
+	t.Run("Limit() uses ROWNUM on legacy version", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			OrderBy("id", gqb.Desc).
+			Limit(10).
+			Get("example")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, `SELECT * FROM (SELECT * FROM "EXAMPLE" ORDER BY "ID" DESC) WHERE ROWNUM <= 10`, m.query)
+	})
+
+	t.Run("Limit() and Offset() use ROWNUM on legacy version", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			Where("active", 1, gqb.Equal).
+			OrderBy("id", gqb.Desc).
+			Limit(10).
+			Offset(20).
+			Get("example")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, `SELECT * FROM (SELECT "GQB_Q".*, ROWNUM "GQB_RN" FROM (SELECT * FROM "EXAMPLE" WHERE ("ACTIVE" = :1) ORDER BY "ID" DESC) "GQB_Q" WHERE ROWNUM <= 30) WHERE "GQB_RN" > 20`, m.query)
+		assert.Equal(t, []interface{}{1}, m.binds)
+	})
+
+	t.Run("Ident keeps case of each part", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			Select(gqb.Ident{"MixedCase"}, "id").
+			Get(gqb.Ident{"app", "Users"})
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, `SELECT "MixedCase", "ID" FROM "app"."Users"`, m.query)
+	})
+}
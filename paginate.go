@@ -0,0 +1,281 @@
+package gqb
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Cursor is struct for keyset pagination.
+// OrderBy determines sort keys of pagination, so sort keys should be unique in combination, e.g. contains primary key.
+// After or Before accepts opaque token which is returned as Next or Prev of CursorPage.
+type Cursor struct {
+	OrderBy []Order
+	After   string
+	Before  string
+	Size    int64
+}
+
+// CursorPage is result of keyset pagination.
+// Next and Prev are empty if there is no more page for the direction.
+type CursorPage struct {
+	Items Results
+	Next  string
+	Prev  string
+}
+
+// Execute keyset pagination query and get page
+func (q *QueryBuilder) Paginate(ctx context.Context, table interface{}, c Cursor) (*CursorPage, error) {
+	q = q.derive()
+	if c.Size <= 0 {
+		return nil, fmt.Errorf("cursor size must be greater than zero")
+	} else if len(c.OrderBy) == 0 {
+		return nil, fmt.Errorf("cursor must have at least one order")
+	} else if c.After != "" && c.Before != "" {
+		return nil, fmt.Errorf("cursor must not have both of after and before")
+	}
+	for _, o := range c.OrderBy {
+		if o.sort == Rand {
+			return nil, fmt.Errorf("cursor could not use random order")
+		} else if err := o.validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	reverse := c.Before != ""
+	token := c.After
+	if reverse {
+		token = c.Before
+	}
+	if token != "" {
+		values, err := decodeCursor(token, len(c.OrderBy))
+		if err != nil {
+			return nil, err
+		}
+		q.restrict(keysetCondition{
+			orders:  c.OrderBy,
+			values:  values,
+			reverse: reverse,
+			combine: And,
+		})
+	}
+
+	// Sort keys determine order, and reverse order is used to read previous page
+	q.orders = []Order{}
+	for _, o := range c.OrderBy {
+		if reverse {
+			o = o.reverse()
+		}
+		q.orders = append(q.orders, o)
+	}
+	// Fetch one more row to check whether next page exists
+	q.limit = c.Size + 1
+	q.offset = 0
+
+	results, err := q.GetContext(ctx, table)
+	if err != nil {
+		return nil, err
+	}
+	more := int64(len(results)) > c.Size
+	if more {
+		results = results[:c.Size]
+	}
+	if reverse {
+		for i, j := 0, len(results)-1; i < j; i, j = i+1, j-1 {
+			results[i], results[j] = results[j], results[i]
+		}
+	}
+
+	page := &CursorPage{Items: results}
+	if len(results) == 0 {
+		return page, nil
+	}
+	if more || reverse {
+		if page.Next, err = encodeCursor(results[len(results)-1], c.OrderBy); err != nil {
+			return nil, err
+		}
+	}
+	if (more && reverse) || (!reverse && c.After != "") {
+		if page.Prev, err = encodeCursor(results[0], c.OrderBy); err != nil {
+			return nil, err
+		}
+	}
+	return page, nil
+}
+
+// cursorTime is time value in cursor token
+type cursorTime struct {
+	Time string `json:"time"`
+}
+
+// encodeCursor() encodes sort key values of result to opaque token
+func encodeCursor(r *Result, orders []Order) (string, error) {
+	values := []interface{}{}
+	for _, o := range orders {
+		// Result column name doesn't contain table name
+		field := lastIdentPart(o.field)
+		v, ok := lookupValue(r, field)
+		if !ok {
+			return "", fmt.Errorf("cursor field %s doesn't exist in result", field)
+		}
+		// Time value keeps sub-second precision and time zone, it is tagged in order to distinguish from string value
+		if t, ok := v.(time.Time); ok {
+			v = cursorTime{Time: t.Format(time.RFC3339Nano)}
+		}
+		values = append(values, v)
+	}
+	buf, err := json.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// Get value of result column by name.
+// Column name is compared case-insensitively if it doesn't match exactly, because driver like Oracle folds case of identifiers.
+func lookupValue(r *Result, name string) (interface{}, bool) {
+	if v, ok := r.values[name]; ok {
+		return v, true
+	}
+	for k, v := range r.values {
+		if strings.EqualFold(k, name) {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// decodeCursor() decodes opaque token to sort key values
+func decodeCursor(token string, size int) ([]interface{}, error) {
+	buf, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor token: %s", err.Error())
+	}
+	values := []interface{}{}
+	dec := json.NewDecoder(strings.NewReader(string(buf)))
+	dec.UseNumber()
+	if err := dec.Decode(&values); err != nil {
+		return nil, fmt.Errorf("invalid cursor token: %s", err.Error())
+	} else if len(values) != size {
+		return nil, fmt.Errorf("cursor token doesn't match to orders")
+	}
+	// Keep integer value as int64 in order to avoid losing precision
+	for i, v := range values {
+		if m, ok := v.(map[string]interface{}); ok {
+			s, _ := m["time"].(string)
+			t, err := time.Parse(time.RFC3339Nano, s)
+			if err != nil {
+				return nil, fmt.Errorf("invalid cursor token: %s", err.Error())
+			}
+			values[i] = t
+		} else if n, ok := v.(json.Number); ok {
+			if iv, err := n.Int64(); err == nil {
+				values[i] = iv
+			} else if fv, err := n.Float64(); err == nil {
+				values[i] = fv
+			}
+		}
+	}
+	return values, nil
+}
+
+// Pagination is result of offset pagination
+type Pagination struct {
+	Items    Results
+	Total    int64
+	Page     int64
+	PerPage  int64
+	LastPage int64
+}
+
+// Execute COUNT query and offset pagination query, and get page with total count.
+// Both queries share stacked conditions, so pass *sql.Tx to New() if you need consistent result between them.
+func (q *QueryBuilder) Page(ctx context.Context, table interface{}, page, perPage int64) (*Pagination, error) {
+	q = q.derive()
+	defer q.Reset()
+	if page <= 0 {
+		return nil, fmt.Errorf("page must be greater than zero")
+	} else if perPage <= 0 {
+		return nil, fmt.Errorf("perPage must be greater than zero")
+	}
+
+	query, binds, err := q.buildCountQuery(ctx, table)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := q.db.QueryContext(ctx, query, binds...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	results, err := q.scan(rows)
+	if err != nil {
+		return nil, err
+	} else if len(results) == 0 {
+		return nil, fmt.Errorf("count query returns no rows")
+	}
+	total, err := results[0].Int64("total")
+	if err != nil {
+		return nil, err
+	}
+
+	q.limit = perPage
+	q.offset = (page - 1) * perPage
+	items, err := q.GetContext(ctx, table)
+	if err != nil {
+		return nil, err
+	}
+	lastPage := (total + perPage - 1) / perPage
+	if lastPage == 0 {
+		lastPage = 1
+	}
+	return &Pagination{
+		Items:    items,
+		Total:    total,
+		Page:     page,
+		PerPage:  perPage,
+		LastPage: lastPage,
+	}, nil
+}
+
+// Build COUNT query from stacked conditions, joins and groups.
+// Query with GROUP BY is wrapped by subquery in order to count groups.
+func (q *QueryBuilder) buildCountQuery(ctx context.Context, table interface{}) (string, []interface{}, error) {
+	if q.err != nil {
+		return "", nil, q.err
+	}
+	mainTable, err := q.formatTable(table)
+	if err != nil {
+		return "", nil, err
+	}
+	wheres, err := q.conditions(ctx, table)
+	if err != nil {
+		return "", nil, err
+	}
+	where, binds := buildWhere(q.compat, wheres, []interface{}{})
+	// Count column is quoted exactly in order to read it as "total" even if driver folds case of identifiers like Oracle
+	total := quote(q.compat, Ident{"total"})
+	if len(q.groupBy) == 0 {
+		return fmt.Sprintf(
+			"SELECT COUNT(*) AS %s FROM %s%s%s",
+			total,
+			mainTable,
+			buildJoin(q.compat, q.joins, targetTable(table)),
+			where,
+		), binds, nil
+	}
+	return fmt.Sprintf(
+		"SELECT COUNT(*) AS %s FROM %s",
+		total,
+		aliasTable(q.compat, fmt.Sprintf(
+			"(SELECT 1 FROM %s%s%s%s)",
+			mainTable,
+			buildJoin(q.compat, q.joins, targetTable(table)),
+			where,
+			buildGroupBy(q.compat, q.groupBy),
+		), quote(q.compat, "gqb_count")),
+	), binds, nil
+}
@@ -1,6 +1,9 @@
 package gqb_test
 
 import (
+	"context"
+	"database/sql/driver"
+	"encoding/base64"
 	"testing"
 	"time"
 
@@ -341,4 +344,451 @@ func runPostgresTest(t *testing.T) {
 			assert.Equal(t, 1, v)
 		}
 	})
+
+	t.Run("WhereRaw() with bind parameters rewrites placeholders", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			Where("id", 1, gqb.Equal).
+			WhereRaw("lower(email) = $1 OR lower(alias) = $1 OR name = $2", "john@example.com", "John").
+			OrWhereRaw("created_at > ?", "2018-01-01").
+			Get("example")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, `SELECT * FROM "example" WHERE ("id" = $1) AND (lower(email) = $2 OR lower(alias) = $3 OR name = $4) OR (created_at > $5)`, m.query)
+		assert.Equal(t, []interface{}{1, "john@example.com", "john@example.com", "John", "2018-01-01"}, m.binds)
+	})
+
+	t.Run("Select() contains raw field with bind parameters", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			Select("id", gqb.Raw("COALESCE(name, $1) AS name").Bind("unknown")).
+			Where("id", 1, gqb.Equal).
+			Get("example")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, `SELECT "id", COALESCE(name, $1) AS name FROM "example" WHERE ("id" = $2)`, m.query)
+		assert.Equal(t, []interface{}{"unknown", 1}, m.binds)
+	})
+
+	t.Run("WhereRaw() returns error if placeholder is out of range", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			WhereRaw("id = $2", 1).
+			Get("example")
+		assert.Error(t, err)
+		assert.Equal(t, "", m.query)
+	})
+
+	t.Run("RawQuery() rewrites named parameters", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			RawQuery(context.Background(), "SELECT id::text FROM example WHERE id = :id OR parent_id = :id OR name = @name", gqb.Params{
+				"id":   10,
+				"name": "John",
+			})
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, "SELECT id::text FROM example WHERE id = $1 OR parent_id = $2 OR name = $3", m.query)
+		assert.Equal(t, []interface{}{10, 10, "John"}, m.binds)
+	})
+
+	t.Run("Query() rewrites placeholders", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			Query(context.Background(), "SELECT * FROM example WHERE id = ? OR name = ?", 1, "John")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, "SELECT * FROM example WHERE id = $1 OR name = $2", m.query)
+		assert.Equal(t, []interface{}{1, "John"}, m.binds)
+	})
+
+	t.Run("Exec() rewrites placeholders", func(t *testing.T) {
+		m := &mockExecutor{}
+		now := time.Now()
+		_, err := gqb.New(m).
+			Exec(context.Background(), "UPDATE example SET updated_at = $2 WHERE id = $1", 1, now)
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, "UPDATE example SET updated_at = $1 WHERE id = $2", m.query)
+		assert.Equal(t, []interface{}{now.Format("2006-01-02 15:04:05"), 1}, m.binds)
+	})
+
+	t.Run("Paginate() with before cursor uses OR chain for mixed directions", func(t *testing.T) {
+		m := &mockExecutor{}
+		token := base64.RawURLEncoding.EncodeToString([]byte(`["John",10]`))
+		_, err := gqb.New(m).
+			Where("status", 1, gqb.Equal).
+			Paginate(context.Background(), "example", gqb.Cursor{
+				OrderBy: []gqb.Order{gqb.NewOrder("name", gqb.Asc), gqb.NewOrder("id", gqb.Desc)},
+				Before:  token,
+				Size:    20,
+			})
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, `SELECT * FROM "example" WHERE ("status" = $1) AND (("name" < $2) OR ("name" = $3 AND "id" > $4)) ORDER BY "name" DESC, "id" ASC LIMIT 21`, m.query)
+		assert.Equal(t, []interface{}{1, "John", "John", int64(10)}, m.binds)
+	})
+
+	t.Run("Page() wraps count query by subquery with GROUP BY", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			Select("company_id", gqb.Raw("COUNT(*) AS cnt")).
+			Where("status", 1, gqb.Equal).
+			GroupBy("company_id").
+			Page(context.Background(), "example", 1, 20)
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, `SELECT COUNT(*) AS "total" FROM (SELECT 1 FROM "example" WHERE ("status" = $1) GROUP BY "company_id") AS "gqb_count"`, m.query)
+		assert.Equal(t, []interface{}{1}, m.binds)
+	})
+
+	t.Run("Page() counts groups and executes grouped items query", func(t *testing.T) {
+		db, c := openRows(t,
+			rowsResult{columns: []string{"total"}, values: [][]driver.Value{{int64(3)}}},
+			rowsResult{columns: []string{"company_id", "cnt"}, values: [][]driver.Value{{int64(1), int64(5)}, {int64(2), int64(8)}}},
+		)
+		page, err := gqb.New(db).
+			Select("company_id", gqb.Raw("COUNT(*) AS cnt")).
+			Where("status", 1, gqb.Equal).
+			GroupBy("company_id").
+			Page(context.Background(), "example", 1, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{
+			`SELECT COUNT(*) AS "total" FROM (SELECT 1 FROM "example" WHERE ("status" = $1) GROUP BY "company_id") AS "gqb_count"`,
+			`SELECT "company_id", COUNT(*) AS cnt FROM "example" WHERE ("status" = $1) GROUP BY "company_id" LIMIT 2`,
+		}, c.queries)
+		assert.Equal(t, int64(3), page.Total)
+		assert.Equal(t, int64(2), page.LastPage)
+		assert.Equal(t, 2, len(page.Items))
+	})
+
+	t.Run("Page() returns error on invalid page", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).Page(context.Background(), "example", 0, 20)
+		assert.Error(t, err)
+		assert.Equal(t, "", m.query)
+	})
+
+	t.Run("Tenant() adds tenant condition from context", func(t *testing.T) {
+		m := &mockExecutor{}
+		ctx := gqb.WithTenant(context.Background(), 10)
+		_, err := gqb.New(m, gqb.Tenant(gqb.TenantPolicy{})).
+			Join("users", "id", "id", gqb.Equal).
+			Where("id", 1, gqb.Equal).
+			OrWhere("id", 2, gqb.Equal).
+			GetContext(ctx, "example")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, `SELECT * FROM "example" JOIN "users" ON ("example"."id" = "users"."id") WHERE (("id" = $1) OR ("id" = $2)) AND ("example"."tenant_id" = $3)`, m.query)
+		assert.Equal(t, []interface{}{1, 2, 10}, m.binds)
+	})
+
+	t.Run("Tenant() injects tenant value on Insert", func(t *testing.T) {
+		m := &mockExecutor{}
+		ctx := gqb.WithTenant(context.Background(), 10)
+		_, err := gqb.New(m, gqb.Tenant(gqb.TenantPolicy{Column: "company_id"})).
+			InsertContext(ctx, "example", gqb.Data{
+				"name": "John Smith",
+			})
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, `INSERT INTO "example" ("company_id", "name") VALUES ($1, $2)`, m.query)
+		assert.Equal(t, []interface{}{10, "John Smith"}, m.binds)
+	})
+
+	t.Run("Tenant() refuses query without tenant", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m, gqb.Tenant(gqb.TenantPolicy{})).
+			Where("id", 1, gqb.Equal).
+			DeleteContext(context.Background(), "example")
+		assert.Equal(t, gqb.ErrNoTenant, err)
+		assert.Equal(t, "", m.query)
+	})
+
+	t.Run("Tenant() doesn't scope global tables", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m, gqb.Tenant(gqb.TenantPolicy{GlobalTables: []string{"plans"}})).
+			Where("id", 1, gqb.Equal).
+			UpdateContext(context.Background(), gqb.Alias("plans", "P"), gqb.Data{"name": "Free"})
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, `UPDATE "plans" AS "P" SET "name" = $1 WHERE ("id" = $2)`, m.query)
+	})
+
+	t.Run("Tenant() refuses to move rows to another tenant", func(t *testing.T) {
+		m := &mockExecutor{}
+		ctx := gqb.WithTenant(context.Background(), 10)
+		_, err := gqb.New(m, gqb.Tenant(gqb.TenantPolicy{})).
+			Where("id", 1, gqb.Equal).
+			UpdateContext(ctx, "example", gqb.Data{"tenant_id": 99})
+		assert.Error(t, err)
+		_, err = gqb.New(m, gqb.Tenant(gqb.TenantPolicy{})).
+			BulkUpdateContext(ctx, "example", "id", []gqb.Data{
+				{"id": 1, "tenant_id": 10},
+				{"id": 2, "tenant_id": 99},
+			})
+		assert.Error(t, err)
+		assert.Equal(t, "", m.query)
+
+		_, err = gqb.New(m, gqb.Tenant(gqb.TenantPolicy{})).
+			Where("id", 1, gqb.Equal).
+			UpdateContext(ctx, "example", gqb.Data{"tenant_id": 10})
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, `UPDATE "example" SET "tenant_id" = $1 WHERE ("id" = $2) AND ("tenant_id" = $3)`, m.query)
+	})
+
+	t.Run("BulkLoad() falls back to chunked bulk insert", func(t *testing.T) {
+		m := &affectedExecutor{affected: 1}
+		_, err := gqb.New(m).
+			BulkLoad(context.Background(), "example", []string{"id", "name"}, gqb.RowsFromSlice([][]interface{}{
+				{1, "John Smith"},
+			}))
+		assert.NoError(t, err)
+		assert.Equal(t, `INSERT INTO "example" ("id", "name") VALUES ($1, $2)`, m.query)
+	})
+
+	t.Run("BulkLoad() with COPY requires prepared statement", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m, gqb.Dialect("pq")).
+			BulkLoad(context.Background(), "example", []string{"id", "name"}, gqb.RowsFromSlice([][]interface{}{
+				{1, "John Smith"},
+			}))
+		assert.Error(t, err)
+		assert.Equal(t, "", m.query)
+	})
+
+	t.Run("BulkUpdate query uses FROM VALUES list", func(t *testing.T) {
+		m := &mockExecutor{}
+		ctx := gqb.WithTenant(context.Background(), 10)
+		_, err := gqb.New(m, gqb.Tenant(gqb.TenantPolicy{})).
+			BulkUpdateContext(ctx, "example", "id", []gqb.Data{
+				gqb.Data{"id": 1, "name": "John Smith"},
+				gqb.Data{"id": 2, "name": "Jane Smith"},
+			})
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, `UPDATE "example" SET "name" = "v"."gqb_1" FROM (SELECT "id", "name" FROM "example" WHERE false UNION ALL VALUES ($1, $2), ($3, $4)) AS "v" ("gqb_0", "gqb_1") WHERE ("tenant_id" = $5) AND ("example"."id" = "v"."gqb_0")`, m.query)
+		assert.Equal(t, []interface{}{1, "John Smith", 2, "Jane Smith", 10}, m.binds)
+	})
+
+	t.Run("Update query with JOIN", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			Join("users", "user_id", "id", gqb.Equal).
+			Where("users.active", 0, gqb.Equal).
+			Update("example", gqb.Data{"name": "Jane Smith"})
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, `UPDATE "example" SET "name" = $1 FROM "users" WHERE ("users"."active" = $2) AND ("example"."user_id" = "users"."id")`, m.query)
+		assert.Equal(t, []interface{}{"Jane Smith", 0}, m.binds)
+	})
+
+	t.Run("Delete query with JOIN", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			Join("users", "user_id", "id", gqb.Equal).
+			Where("users.active", 0, gqb.Equal).
+			Delete("example")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, `DELETE FROM "example" USING "users" WHERE ("users"."active" = $1) AND ("example"."user_id" = "users"."id")`, m.query)
+	})
+
+	t.Run("Delete query with ORDER BY and LIMIT uses ctid subquery", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			Where("active", 0, gqb.Equal).
+			OrderBy("id", gqb.Asc).
+			Limit(10).
+			Delete("example")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, `DELETE FROM "example" WHERE ctid IN (SELECT "example".ctid FROM "example" WHERE ("active" = $1) ORDER BY "id" ASC LIMIT 10)`, m.query)
+		assert.Equal(t, []interface{}{0}, m.binds)
+	})
+
+	t.Run("InsertFrom() builds INSERT ... SELECT query", func(t *testing.T) {
+		m := &mockExecutor{}
+		src := gqb.New(nil).
+			Select("id", "user_id", "total").
+			Where("status", "closed", gqb.Equal).
+			Where("created_at", "2018-01-01", gqb.Lt).
+			From("orders")
+		_, err := gqb.New(m).
+			InsertFrom(context.Background(), "archive_orders", []string{"id", "user_id", "total"}, src)
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, `INSERT INTO "archive_orders" ("id", "user_id", "total") SELECT "id", "user_id", "total" FROM "orders" WHERE ("status" = $1) AND ("created_at" < $2)`, m.query)
+		assert.Equal(t, []interface{}{"closed", "2018-01-01"}, m.binds)
+	})
+
+	t.Run("InsertFrom() builds source query on the dialect of executing builder", func(t *testing.T) {
+		m := &mockExecutor{}
+		src := gqb.New(nil, gqb.Dialect("mysql")).
+			Where("status", "closed", gqb.Equal).
+			From("orders")
+		_, err := gqb.New(m).
+			InsertFrom(context.Background(), "archive_orders", []string{"id"}, src.Select("id"))
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, `INSERT INTO "archive_orders" ("id") SELECT "id" FROM "orders" WHERE ("status" = $1)`, m.query)
+	})
+
+	t.Run("InsertFrom() scopes source rows by tenant policy", func(t *testing.T) {
+		m := &mockExecutor{}
+		ctx := gqb.WithTenant(context.Background(), 10)
+		src := gqb.New(nil).
+			Select("id", "tenant_id").
+			Where("status", "closed", gqb.Equal).
+			From("orders")
+		_, err := gqb.New(m, gqb.Tenant(gqb.TenantPolicy{})).
+			InsertFrom(ctx, "archive_orders", []string{"id", "tenant_id"}, src)
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, `INSERT INTO "archive_orders" ("id", "tenant_id") SELECT "id", "tenant_id" FROM "orders" WHERE ("status" = $1) AND ("tenant_id" = $2)`, m.query)
+		assert.Equal(t, []interface{}{"closed", 10}, m.binds)
+
+		m = &mockExecutor{}
+		_, err = gqb.New(m, gqb.Tenant(gqb.TenantPolicy{})).
+			InsertFrom(ctx, "archive_orders", []string{"id"}, src)
+		assert.Error(t, err)
+		assert.Equal(t, "", m.query)
+
+		_, err = gqb.New(m, gqb.Tenant(gqb.TenantPolicy{})).
+			InsertFrom(context.Background(), "archive_orders", []string{"id", "tenant_id"}, src)
+		assert.Equal(t, gqb.ErrNoTenant, err)
+		assert.Equal(t, "", m.query)
+	})
+
+	t.Run("Query() and Exec() are refused under tenant policy", func(t *testing.T) {
+		m := &mockExecutor{}
+		ctx := gqb.WithTenant(context.Background(), 10)
+		_, err := gqb.New(m, gqb.Tenant(gqb.TenantPolicy{})).
+			Query(ctx, "SELECT * FROM example")
+		assert.Equal(t, gqb.ErrTenantRawQuery, err)
+		_, err = gqb.New(m, gqb.Tenant(gqb.TenantPolicy{})).
+			RawQuery(ctx, "SELECT * FROM example WHERE id = :id", gqb.Params{"id": 1})
+		assert.Equal(t, gqb.ErrTenantRawQuery, err)
+		_, err = gqb.New(m, gqb.Tenant(gqb.TenantPolicy{})).
+			Exec(ctx, "DELETE FROM example")
+		assert.Equal(t, gqb.ErrTenantRawQuery, err)
+		assert.Equal(t, "", m.query)
+	})
+
+	t.Run("InsertFrom() returns error if the builder has stacked state", func(t *testing.T) {
+		m := &mockExecutor{}
+		src := gqb.New(nil).Select("id").From("orders")
+		_, err := gqb.New(m).
+			Where("zzz", 5, gqb.Equal).
+			InsertFrom(context.Background(), "archive_orders", []string{"id"}, src)
+		assert.Error(t, err)
+		assert.Equal(t, "", m.query)
+	})
+
+	t.Run("InsertFrom() returns error without source table", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			InsertFrom(context.Background(), "archive_orders", []string{"id"}, gqb.New(nil).Select("id"))
+		assert.Error(t, err)
+		assert.Equal(t, "", m.query)
+	})
+
+	t.Run("InsertReturning() uses RETURNING", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			InsertReturning(context.Background(), "example", gqb.Data{"name": "John Smith"})
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, `INSERT INTO "example" ("name") VALUES ($1) RETURNING *`, m.query)
+	})
+
+	t.Run("InsertReturningInto() uses RETURNING", func(t *testing.T) {
+		m := &mockExecutor{}
+		var id int64
+		err := gqb.New(m).
+			InsertReturningInto(context.Background(), "example", gqb.Data{"name": "John Smith"}, gqb.Params{"id": &id})
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, `INSERT INTO "example" ("name") VALUES ($1) RETURNING "id"`, m.query)
+	})
+
+	t.Run("Upsert() doesn't update created timestamp", func(t *testing.T) {
+		m := &mockExecutor{}
+		now := time.Date(2018, 1, 2, 3, 4, 5, 0, time.UTC)
+		_, err := gqb.New(m, gqb.Timestamps(gqb.Timestamp{}), gqb.Clock(func() time.Time { return now })).
+			Upsert("example", gqb.Data{"id": 1, "name": "John Smith"}, "id")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, `INSERT INTO "example" ("created_at", "id", "name", "updated_at") VALUES ($1, $2, $3, $4) ON CONFLICT ("id") DO UPDATE SET "name" = EXCLUDED."name", "updated_at" = EXCLUDED."updated_at"`, m.query)
+	})
+
+	t.Run("Upsert() updates conflicted row of the same tenant only", func(t *testing.T) {
+		m := &mockExecutor{}
+		ctx := gqb.WithTenant(context.Background(), 10)
+		_, err := gqb.New(m, gqb.Tenant(gqb.TenantPolicy{})).
+			UpsertContext(ctx, "example", gqb.Data{"email": "john@example.com", "name": "John Smith"}, "email")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, `INSERT INTO "example" ("email", "name", "tenant_id") VALUES ($1, $2, $3) ON CONFLICT ("email") DO UPDATE SET "name" = EXCLUDED."name" WHERE "example"."tenant_id" = EXCLUDED."tenant_id"`, m.query)
+		assert.Equal(t, []interface{}{"john@example.com", "John Smith", 10}, m.binds)
+	})
+
+	t.Run("Upsert() returns error without conflict columns", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			Upsert("example", gqb.Data{"id": 1, "name": "John Smith"})
+		assert.Error(t, err)
+		assert.Equal(t, "", m.query)
+	})
+
+	t.Run("Alias() quotes alias name as a single part", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			Join("companies", "company_id", "id", gqb.Equal).
+			Get(gqb.Alias(gqb.Ident{"app", "my.users"}, "u.x"))
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, `SELECT * FROM "app"."my.users" AS "u.x" JOIN "companies" ON ("u.x"."company_id" = "companies"."id")`, m.query)
+	})
+
+	t.Run("Quote() escapes embedded double quote", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			Select(`na"me`).
+			Get("example")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, `SELECT "na""me" FROM "example"`, m.query)
+	})
+
+	t.Run("Ident quotes each part exactly", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			Select(gqb.Ident{"u", "first.name"}, "u.id").
+			Join("companies", "company_id", "id", gqb.Equal).
+			Where(gqb.Ident{"u", `na"me`}, "John", gqb.Equal).
+			GroupBy(gqb.Ident{"u", "first.name"}, "u.id").
+			OrderBy(gqb.Ident{"u", "first.name"}, gqb.Asc).
+			Get(gqb.Alias(gqb.Ident{"my.schema", "users"}, "u"))
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, `SELECT "u"."first.name", "u"."id" FROM "my.schema"."users" AS "u" JOIN "companies" ON ("u"."company_id" = "companies"."id") WHERE ("u"."na""me" = $1) GROUP BY "u"."first.name", "u"."id" ORDER BY "u"."first.name" ASC`, m.query)
+	})
+
+	t.Run("Quote() doesn't split dot inside of quoted part", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			Select(`"first.name"`).
+			Get("users")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, `SELECT "first.name" FROM "users"`, m.query)
+	})
+
+	t.Run("SetSearchSchema() qualifies unqualified tables", func(t *testing.T) {
+		gqb.SetSearchSchema("app")
+		defer gqb.SetSearchSchema("")
+
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			Join("companies", "company_id", "id", gqb.Equal).
+			Where("users.id", 1, gqb.Equal).
+			Get("users")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, `SELECT * FROM "app"."users" JOIN "app"."companies" ON ("app"."users"."company_id" = "companies"."id") WHERE ("users"."id" = $1)`, m.query)
+
+		_, err = gqb.New(m).
+			Insert("audit.logs", gqb.Data{"message": "hello"})
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, `INSERT INTO "audit"."logs" ("message") VALUES ($1)`, m.query)
+
+		_, err = gqb.New(m).
+			Get(gqb.Alias("users", "u"))
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, `SELECT * FROM "app"."users" AS "u"`, m.query)
+	})
+
+	t.Run("OrderBy() with random doesn't sort by field", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			OrderBy("id", gqb.Rand).
+			Get("example")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, `SELECT * FROM "example" ORDER BY RANDOM()`, m.query)
+	})
 }
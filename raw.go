@@ -0,0 +1,190 @@
+package gqb
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// RawExpr is raw SQL phrase which carries bind parameters, it is created by Raw("...").Bind().
+// The phrase is split by placeholders on creation, and placeholders are rewritten
+// to driver specific form on build in order to fit running bind index.
+type RawExpr struct {
+	// parts stacks SQL phrases between placeholders, always has len(values)+1 length
+	parts []string
+
+	// values stacks bind value for each placeholder
+	values []interface{}
+
+	// err stacks parse error, it will be returned on query execution
+	err error
+}
+
+// newRawExpr() parses raw SQL phrase and resolves bind value for each placeholder.
+// Placeholder accepts "?" as sequential index, and "$n" as positional index which starts from 1.
+// If Params is supplied as only one bind parameter, placeholder accepts ":name" or "@name" as named parameter.
+// Positional bind parameters which are not used by any placeholders are error as well as short ones.
+// Placeholders inside of quotes and comments like "-- ..." or "/* ... */" are ignored.
+func newRawExpr(raw string, args []interface{}) RawExpr {
+	// Raw phrase without any bind parameters outputs as it is
+	if len(args) == 0 {
+		return RawExpr{parts: []string{raw}}
+	}
+	var params Params
+	var named bool
+	if len(args) == 1 {
+		params, named = args[0].(Params)
+	}
+
+	r := RawExpr{}
+	used := make([]bool, len(args))
+	var seq int
+	var start int
+	var quote byte
+	for i := 0; i < len(raw); i++ {
+		b := raw[i]
+		if quote != 0 {
+			if b == quote {
+				quote = 0
+			}
+			continue
+		}
+		switch b {
+		case '\'', '"', '`':
+			quote = b
+		case '-':
+			// Skip line comment to the end of line
+			if i+1 < len(raw) && raw[i+1] == '-' {
+				for i < len(raw) && raw[i] != '\n' {
+					i++
+				}
+			}
+		case '/':
+			// Skip block comment
+			if i+1 < len(raw) && raw[i+1] == '*' {
+				end := strings.Index(raw[i+2:], "*/")
+				if end == -1 {
+					return RawExpr{
+						parts: []string{raw},
+						err:   fmt.Errorf("raw phrase has unterminated comment: %s", raw),
+					}
+				}
+				i += end + 3
+			}
+		case '?':
+			if named {
+				continue
+			}
+			if seq >= len(args) {
+				return RawExpr{
+					parts: []string{raw},
+					err:   fmt.Errorf("raw phrase has more placeholders than bind parameters: %s", raw),
+				}
+			}
+			r.parts = append(r.parts, raw[start:i])
+			r.values = append(r.values, args[seq])
+			used[seq] = true
+			seq++
+			start = i + 1
+		case '$':
+			if named {
+				continue
+			}
+			j := i + 1
+			for j < len(raw) && isDigit(raw[j]) {
+				j++
+			}
+			if j == i+1 {
+				continue
+			}
+			index, _ := strconv.Atoi(raw[i+1 : j])
+			if index < 1 || index > len(args) {
+				return RawExpr{
+					parts: []string{raw},
+					err:   fmt.Errorf("placeholder $%d is out of bind parameters range: %s", index, raw),
+				}
+			}
+			r.parts = append(r.parts, raw[start:i])
+			r.values = append(r.values, args[index-1])
+			used[index-1] = true
+			start = j
+			i = j - 1
+		case ':', '@':
+			if !named {
+				continue
+			}
+			// Skip doubled character like "::" of type cast or "@@" of system variable
+			if i+1 < len(raw) && raw[i+1] == b {
+				i++
+				continue
+			}
+			j := i + 1
+			for j < len(raw) && (isNameChar(raw[j]) || (j > i+1 && isDigit(raw[j]))) {
+				j++
+			}
+			if j == i+1 {
+				continue
+			}
+			name := raw[i+1 : j]
+			v, ok := params[name]
+			if !ok {
+				return RawExpr{
+					parts: []string{raw},
+					err:   fmt.Errorf("named parameter %s is not found in params: %s", name, raw),
+				}
+			}
+			r.parts = append(r.parts, raw[start:i])
+			r.values = append(r.values, v)
+			start = j
+			i = j - 1
+		}
+	}
+	if quote != 0 {
+		return RawExpr{
+			parts: []string{raw},
+			err:   fmt.Errorf("raw phrase has unterminated quote: %s", raw),
+		}
+	}
+	// Named parameters are looked up by name, so unused ones are allowed
+	for i := range used {
+		if !named && !used[i] {
+			return RawExpr{
+				parts: []string{raw},
+				err:   fmt.Errorf("raw phrase has fewer placeholders than bind parameters: %s", raw),
+			}
+		}
+	}
+	r.parts = append(r.parts, raw[start:])
+	return r
+}
+
+// Check byte is digit
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
+
+// Check byte is available for the first character of named parameter
+func isNameChar(b byte) bool {
+	return b == '_' || (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
+
+// Build raw phrase with rewriting placeholders and append bind parameters.
+// Placeholders are written for dialect which is set by SetDriver().
+func (r RawExpr) Build(binds []interface{}) (string, []interface{}) {
+	return r.buildCompat(defaultDialect(), binds)
+}
+
+// Build raw phrase with placeholders of the dialect
+func (r RawExpr) buildCompat(compat Compat, binds []interface{}) (string, []interface{}) {
+	phrase := r.parts[0]
+	for i, v := range r.values {
+		phrase += compat.PlaceHolder(len(binds)+1) + r.parts[i+1]
+		binds = bind(compat, binds, v)
+	}
+	return phrase, binds
+}
+
+// Return parse error of raw phrase
+func (r RawExpr) validate() error {
+	return r.err
+}
@@ -0,0 +1,57 @@
+package gqb
+
+import (
+	"reflect"
+)
+
+var (
+	// SQLSTATE codes which mean transaction can be retried on PostgreSQL, CockroachDB and YugabyteDB
+	retryableStates = map[string]bool{
+		"40001": true, // serialization_failure, CockroachDB returns it for all retry errors
+		"40P01": true, // deadlock_detected
+	}
+
+	// Error numbers which mean transaction can be retried on MySQL and MariaDB
+	retryableNumbers = map[uint64]bool{
+		1205: true, // ER_LOCK_WAIT_TIMEOUT
+		1213: true, // ER_LOCK_DEADLOCK
+	}
+)
+
+// IsRetryable returns true if error means transaction can be retried like serialization failure or deadlock.
+// Error is inspected without importing drivers, it accepts error which has SQLState() method like pgx,
+// "Code" string field like lib/pq, or "Number" integer field like go-sql-driver/mysql.
+func IsRetryable(err error) bool {
+	for ; err != nil; err = unwrapError(err) {
+		if s, ok := err.(interface{ SQLState() string }); ok {
+			if retryableStates[s.SQLState()] {
+				return true
+			}
+			continue
+		}
+		v := derefValue(reflect.ValueOf(err))
+		if v.Kind() != reflect.Struct {
+			continue
+		}
+		if f := v.FieldByName("Code"); f.IsValid() && f.Kind() == reflect.String && retryableStates[f.String()] {
+			return true
+		}
+		if f := v.FieldByName("Number"); f.IsValid() {
+			switch f.Kind() {
+			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+				if retryableNumbers[f.Uint()] {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// Get wrapped error through Unwrap() method, errors.Unwrap isn't used in order to support old Go versions
+func unwrapError(err error) error {
+	if u, ok := err.(interface{ Unwrap() error }); ok {
+		return u.Unwrap()
+	}
+	return nil
+}
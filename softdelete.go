@@ -0,0 +1,98 @@
+package gqb
+
+import (
+	"context"
+	"fmt"
+
+	"database/sql"
+)
+
+// trashedMode indicates how to treat soft deleted rows
+type trashedMode int
+
+const (
+	// withoutTrashed excludes soft deleted rows, this is default
+	withoutTrashed trashedMode = iota
+
+	// withTrashed includes soft deleted rows
+	withTrashed
+
+	// onlyTrashed treats soft deleted rows only
+	onlyTrashed
+)
+
+// SoftDelete option enables soft delete with specified column.
+// The column should be nullable datetime column, and NULL indicates the row is not deleted.
+// SELECT and UPDATE query exclude soft deleted rows automatically,
+// and DELETE query sets current time to the column instead of deleting rows.
+func SoftDelete(column string) Option {
+	return func(q *QueryBuilder) {
+		q.softDelete = column
+	}
+}
+
+// Include soft deleted rows
+func (q *QueryBuilder) WithTrashed() *QueryBuilder {
+	q = q.derive()
+	q.trashed = withTrashed
+	return q
+}
+
+// Treat soft deleted rows only
+func (q *QueryBuilder) OnlyTrashed() *QueryBuilder {
+	q = q.derive()
+	q.trashed = onlyTrashed
+	return q
+}
+
+// Create soft delete condition corresponds to trashed mode.
+// Returns nil if soft delete is disabled or including soft deleted rows.
+func (q *QueryBuilder) softDeleteCondition(table interface{}) ConditionBuilder {
+	if q.softDelete == "" || q.trashed == withTrashed {
+		return nil
+	}
+	c := condition{
+		comparison: Equal,
+		field:      q.qualify(table, q.softDelete),
+		value:      nil,
+		combine:    And,
+	}
+	if q.trashed == onlyTrashed {
+		c.comparison = NotEqual
+	}
+	return c
+}
+
+// Execute UPDATE query which restores soft deleted rows
+func (q *QueryBuilder) Restore(table interface{}) (sql.Result, error) {
+	return q.RestoreContext(context.Background(), table)
+}
+
+// Execute UPDATE query which restores soft deleted rows with context
+func (q *QueryBuilder) RestoreContext(ctx context.Context, table interface{}) (sql.Result, error) {
+	q = q.derive()
+	if q.softDelete == "" {
+		return nil, fmt.Errorf("soft delete is not enabled")
+	}
+	if q.trashed == withoutTrashed {
+		q.trashed = onlyTrashed
+	}
+	return q.updateContext(ctx, table, Data{
+		q.softDelete: nil,
+	}, false)
+}
+
+// Execute DELETE query which deletes rows actually even if soft delete is enabled
+func (q *QueryBuilder) ForceDelete(table interface{}) (sql.Result, error) {
+	return q.ForceDeleteContext(context.Background(), table)
+}
+
+// Execute DELETE query which deletes rows actually even if soft delete is enabled with context.
+// Soft deleted rows are also deleted unless OnlyTrashed() is called.
+func (q *QueryBuilder) ForceDeleteContext(ctx context.Context, table interface{}) (sql.Result, error) {
+	q = q.derive()
+	if q.trashed == withoutTrashed {
+		q.trashed = withTrashed
+	}
+	return q.deleteContext(ctx, table)
+}
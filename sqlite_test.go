@@ -1,6 +1,7 @@
 package gqb_test
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -92,7 +93,7 @@ func runSQLiteTest(t *testing.T) {
 			Offset(10).
 			Get("example")
 		assert.IsType(t, mockError{}, err)
-		assert.Equal(t, `SELECT * FROM "example" OFFSET 10`, m.query)
+		assert.Equal(t, `SELECT * FROM "example" LIMIT -1 OFFSET 10`, m.query)
 		assert.Equal(t, 0, len(m.binds))
 	})
 
@@ -341,4 +342,198 @@ func runSQLiteTest(t *testing.T) {
 			assert.Equal(t, 1, v)
 		}
 	})
+
+	t.Run("WhereRaw() with bind parameters", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			Where("id", 1, gqb.Equal).
+			WhereRaw(`lower(email) = $1 OR "?" = $1`, "john@example.com").
+			Delete("example")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, `DELETE FROM "example" WHERE ("id" = ?) AND (lower(email) = ? OR "?" = ?)`, m.query)
+		assert.Equal(t, []interface{}{1, "john@example.com", "john@example.com"}, m.binds)
+	})
+
+	t.Run("SoftDelete() excludes soft deleted rows", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m, gqb.SoftDelete("deleted_at")).
+			Where("id", 1, gqb.Equal).
+			OrWhere("id", 2, gqb.Equal).
+			Get("example")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, `SELECT * FROM "example" WHERE (("id" = ?) OR ("id" = ?)) AND ("deleted_at" IS NULL)`, m.query)
+		assert.Equal(t, []interface{}{1, 2}, m.binds)
+	})
+
+	t.Run("SoftDelete() qualifies column on JOIN", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m, gqb.SoftDelete("deleted_at")).
+			Join("users", "id", "id", gqb.Equal).
+			OnlyTrashed().
+			Get("example")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, `SELECT * FROM "example" JOIN "users" ON ("example"."id" = "users"."id") WHERE ("example"."deleted_at" IS NOT NULL)`, m.query)
+	})
+
+	t.Run("WithTrashed() includes soft deleted rows", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m, gqb.SoftDelete("deleted_at")).
+			WithTrashed().
+			Get("example")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, `SELECT * FROM "example"`, m.query)
+	})
+
+	t.Run("Delete() with SoftDelete() updates column", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m, gqb.SoftDelete("deleted_at")).
+			Where("id", 1, gqb.Equal).
+			Delete("example")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, `UPDATE "example" SET "deleted_at" = ? WHERE ("id" = ?) AND ("deleted_at" IS NULL)`, m.query)
+		assert.Equal(t, 2, len(m.binds))
+	})
+
+	t.Run("Restore() clears soft delete column", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m, gqb.SoftDelete("deleted_at")).
+			Where("id", 1, gqb.Equal).
+			Restore("example")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, `UPDATE "example" SET "deleted_at" = ? WHERE ("id" = ?) AND ("deleted_at" IS NOT NULL)`, m.query)
+		assert.Equal(t, []interface{}{nil, 1}, m.binds)
+	})
+
+	t.Run("ForceDelete() deletes rows actually", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m, gqb.SoftDelete("deleted_at")).
+			Where("id", 1, gqb.Equal).
+			ForceDelete("example")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, `DELETE FROM "example" WHERE ("id" = ?)`, m.query)
+		assert.Equal(t, []interface{}{1}, m.binds)
+	})
+
+	t.Run("BulkInsert query is split into chunks under bind limit", func(t *testing.T) {
+		m := &affectedExecutor{affected: 10}
+		data := []gqb.Data{}
+		for i := 0; i < 1000; i++ {
+			data = append(data, gqb.Data{"id": i, "name": "John Smith"})
+		}
+		r, err := gqb.New(m).BulkInsert("example", data)
+		assert.NoError(t, err)
+		// 999 binds allow 499 rows in a query, so the last query has 2 rows
+		assert.Equal(t, `INSERT INTO "example" ("id", "name") VALUES (?, ?), (?, ?)`, m.query)
+		assert.Equal(t, []interface{}{998, "John Smith", 999, "John Smith"}, m.binds)
+		affected, _ := r.RowsAffected()
+		assert.Equal(t, int64(30), affected)
+	})
+
+	t.Run("BulkInsert returns error if data has different keys", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			BulkInsert("example", []gqb.Data{
+				gqb.Data{"id": 1, "name": "John Smith"},
+				gqb.Data{"id": 2, "email": "jane@example.com"},
+			})
+		assert.Error(t, err)
+		assert.Equal(t, "", m.query)
+	})
+
+	t.Run("BulkLoad() falls back to chunked bulk insert", func(t *testing.T) {
+		m := &affectedExecutor{affected: 2}
+		loaded, err := gqb.New(m).
+			BulkLoad(context.Background(), "example", []string{"id", "name"}, gqb.RowsFromSlice([][]interface{}{
+				{1, "John Smith"},
+				{2, nil},
+			}))
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), loaded)
+		assert.Equal(t, `INSERT INTO "example" ("id", "name") VALUES (?, ?), (?, ?)`, m.query)
+		assert.Equal(t, []interface{}{1, "John Smith", 2, nil}, m.binds)
+	})
+
+	t.Run("BulkLoad() accepts Ident table", func(t *testing.T) {
+		m := &affectedExecutor{affected: 1}
+		_, err := gqb.New(m).
+			BulkLoad(context.Background(), gqb.Ident{"main", "my.example"}, []string{"id"}, gqb.RowsFromSlice([][]interface{}{
+				{1},
+			}))
+		assert.NoError(t, err)
+		assert.Equal(t, `INSERT INTO "main"."my.example" ("id") VALUES (?)`, m.query)
+
+		_, err = gqb.New(m).
+			BulkLoad(context.Background(), 1, []string{"id"}, gqb.RowsFromSlice([][]interface{}{
+				{1},
+			}))
+		assert.Error(t, err)
+	})
+
+	t.Run("BulkLoad() returns error if row doesn't match to columns", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			BulkLoad(context.Background(), "example", []string{"id", "name"}, gqb.RowsFromSlice([][]interface{}{
+				{1},
+			}))
+		assert.Error(t, err)
+		assert.Equal(t, "", m.query)
+	})
+
+	t.Run("BulkLoad() scopes rows by tenant policy", func(t *testing.T) {
+		m := &affectedExecutor{affected: 2}
+		ctx := gqb.WithTenant(context.Background(), 10)
+		_, err := gqb.New(m, gqb.Tenant(gqb.TenantPolicy{})).
+			BulkLoad(ctx, "example", []string{"id", "name"}, gqb.RowsFromSlice([][]interface{}{
+				{1, "John Smith"},
+				{2, "Jane Smith"},
+			}))
+		assert.NoError(t, err)
+		assert.Equal(t, `INSERT INTO "example" ("id", "name", "tenant_id") VALUES (?, ?, ?), (?, ?, ?)`, m.query)
+		assert.Equal(t, []interface{}{1, "John Smith", 10, 2, "Jane Smith", 10}, m.binds)
+
+		n := &mockExecutor{}
+		_, err = gqb.New(n, gqb.Tenant(gqb.TenantPolicy{})).
+			BulkLoad(ctx, "example", []string{"id", "tenant_id"}, gqb.RowsFromSlice([][]interface{}{
+				{1, 99},
+			}))
+		assert.Error(t, err)
+		_, err = gqb.New(n, gqb.Tenant(gqb.TenantPolicy{})).
+			BulkLoad(context.Background(), "example", []string{"id", "tenant_id"}, gqb.RowsFromSlice([][]interface{}{
+				{1, 99},
+			}))
+		assert.Equal(t, gqb.ErrNoTenant, err)
+		assert.Equal(t, "", n.query)
+	})
+
+	t.Run("Update query with JOIN and LIMIT uses rowid subquery", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			Join("users", "user_id", "id", gqb.Equal).
+			Where("users.active", 0, gqb.Equal).
+			Limit(10).
+			Update("example", gqb.Data{"name": "Jane Smith"})
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, `UPDATE "example" SET "name" = ? WHERE rowid IN (SELECT "example".rowid FROM "example" JOIN "users" ON ("example"."user_id" = "users"."id") WHERE ("users"."active" = ?) LIMIT 10)`, m.query)
+		assert.Equal(t, []interface{}{"Jane Smith", 0}, m.binds)
+	})
+
+	t.Run("Delete query with OFFSET uses negative LIMIT", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			Where("active", 0, gqb.Equal).
+			OrderBy("id", gqb.Asc).
+			Offset(5).
+			Delete("example")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, `DELETE FROM "example" WHERE rowid IN (SELECT "example".rowid FROM "example" WHERE ("active" = ?) ORDER BY "id" ASC LIMIT -1 OFFSET 5)`, m.query)
+	})
+
+	t.Run("OrderBy() with random doesn't sort by field", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			OrderBy("id", gqb.Rand).
+			Get("example")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, `SELECT * FROM "example" ORDER BY RANDOM()`, m.query)
+	})
 }
@@ -0,0 +1,111 @@
+package gqb_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ysugimoto/gqb"
+)
+
+func runSQLServerTest(t *testing.T) {
+	gqb.SetDriver("sqlserver")
+
+	t.Run("Table aliasing", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			Get(gqb.Alias("example", "E"))
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, "SELECT * FROM [example] AS [E]", m.query)
+	})
+
+	t.Run("Where() with placeholders", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			Select("example.id", "name").
+			Where("id", 1, gqb.Equal).
+			Where("name", "John", gqb.Equal).
+			Get("example")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, "SELECT [example].[id], [name] FROM [example] WHERE ([id] = @p1) AND ([name] = @p2)", m.query)
+		assert.Equal(t, []interface{}{1, "John"}, m.binds)
+	})
+
+	t.Run("Limit() and Offset() use OFFSET FETCH", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			OrderBy("id", gqb.Asc).
+			Limit(10).
+			Offset(20).
+			Get("example")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, "SELECT * FROM [example] ORDER BY [id] ASC OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY", m.query)
+	})
+
+	t.Run("Limit() without OrderBy() orders by constant", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			Limit(10).
+			Get("example")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, "SELECT * FROM [example] ORDER BY (SELECT NULL) OFFSET 0 ROWS FETCH NEXT 10 ROWS ONLY", m.query)
+	})
+
+	t.Run("GetOne() fetches first row without OrderBy()", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			Where("id", 1, gqb.Equal).
+			GetOne("example")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, "SELECT * FROM [example] WHERE ([id] = @p1) ORDER BY (SELECT NULL) OFFSET 0 ROWS FETCH NEXT 1 ROWS ONLY", m.query)
+	})
+
+	t.Run("BulkInsert() splits rows by 1000 rows", func(t *testing.T) {
+		m := &affectedExecutor{affected: 1}
+		data := []gqb.Data{}
+		for i := 0; i < 1001; i++ {
+			data = append(data, gqb.Data{"id": i})
+		}
+		_, err := gqb.New(m).BulkInsert("example", data)
+		assert.NoError(t, err)
+		assert.Equal(t, "INSERT INTO [example] ([id]) VALUES (@p1)", m.query)
+		assert.Equal(t, []interface{}{1000}, m.binds)
+	})
+
+	t.Run("OrderBy() with random", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			OrderBy("id", gqb.Rand).
+			Get("example")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, "SELECT * FROM [example] ORDER BY NEWID()", m.query)
+	})
+
+	t.Run("InsertReturning() uses OUTPUT INSERTED", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			InsertReturning(context.Background(), "example", gqb.Data{"name": "John Smith"}, "id", "name")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, "INSERT INTO [example] ([name]) OUTPUT INSERTED.[id], INSERTED.[name] VALUES (@p1)", m.query)
+		assert.Equal(t, []interface{}{"John Smith"}, m.binds)
+	})
+
+	t.Run("Update query with LIMIT returns error", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			Where("id", 1, gqb.Equal).
+			Limit(1).
+			Update("example", gqb.Data{"name": "John Smith"})
+		assert.Error(t, err)
+		assert.Equal(t, "", m.query)
+	})
+
+	t.Run("Quote() escapes embedded bracket", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			Select("na]me").
+			Get("example")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, "SELECT [na]]me] FROM [example]", m.query)
+	})
+}
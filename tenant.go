@@ -0,0 +1,198 @@
+package gqb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ErrNoTenant is returned when query is executed without tenant in context under tenant policy
+var ErrNoTenant = errors.New("tenant is not found in context")
+
+// ErrTenantRawQuery is returned when raw query is executed under tenant policy, because gqb can't scope it by tenant.
+// Use builder without Tenant option for raw query.
+var ErrTenantRawQuery = errors.New("raw query is not allowed under tenant policy")
+
+// tenantKey is context key for tenant value
+type tenantKey struct{}
+
+// TenantPolicy is configuration for multi-tenant row scoping.
+// Column is tenant column name, and GlobalTables are tables which are not scoped by tenant.
+type TenantPolicy struct {
+	Column       string
+	GlobalTables []string
+}
+
+// Tenant option enables multi-tenant row scoping.
+// SELECT, UPDATE and DELETE query add tenant condition which is taken from context,
+// and INSERT query injects tenant value to Data. UPDATE query refuses Data which has another tenant value.
+// Query is refused with ErrNoTenant if context doesn't have tenant, and raw query is refused with ErrTenantRawQuery.
+func Tenant(p TenantPolicy) Option {
+	if p.Column == "" {
+		p.Column = "tenant_id"
+	}
+	return func(q *QueryBuilder) {
+		q.tenant = &p
+	}
+}
+
+// Create context which has tenant value
+func WithTenant(ctx context.Context, tenant interface{}) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// Get tenant value from context
+func TenantFromContext(ctx context.Context) (interface{}, bool) {
+	tenant := ctx.Value(tenantKey{})
+	return tenant, tenant != nil
+}
+
+// Check table is global table which is not scoped by tenant
+func (p *TenantPolicy) isGlobal(table interface{}) bool {
+	if v, ok := table.(alias); ok {
+		table = v.from
+	}
+	var name string
+	switch t := table.(type) {
+	case string:
+		name = t
+	case Ident:
+		name = strings.Join(t, ".")
+	default:
+		return false
+	}
+	for _, t := range p.GlobalTables {
+		if t == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Get tenant value for table.
+// Returns nil if tenant policy is disabled or table is global table.
+func (q *QueryBuilder) tenantValue(ctx context.Context, table interface{}) (interface{}, error) {
+	if q.tenant == nil || q.tenant.isGlobal(table) {
+		return nil, nil
+	}
+	tenant, ok := TenantFromContext(ctx)
+	if !ok {
+		return nil, ErrNoTenant
+	}
+	return tenant, nil
+}
+
+// Create tenant condition for table
+func (q *QueryBuilder) tenantCondition(ctx context.Context, table interface{}) (ConditionBuilder, error) {
+	tenant, err := q.tenantValue(ctx, table)
+	if err != nil || tenant == nil {
+		return nil, err
+	}
+	return condition{
+		comparison: Equal,
+		field:      q.qualify(table, q.tenant.Column),
+		value:      tenant,
+		combine:    And,
+	}, nil
+}
+
+// Create copied Data which has tenant value.
+// Returns error if Data already has another tenant value.
+func (q *QueryBuilder) tenantData(ctx context.Context, table interface{}, data Data) (Data, error) {
+	tenant, err := q.tenantValue(ctx, table)
+	if err != nil || tenant == nil {
+		return data, err
+	}
+	if v, ok := data[q.tenant.Column]; ok && v != nil {
+		if !reflect.DeepEqual(v, tenant) {
+			return nil, fmt.Errorf("data has different tenant from context: %v", v)
+		}
+		return data, nil
+	}
+	injected := Data{}
+	for k, v := range data {
+		injected[k] = v
+	}
+	injected[q.tenant.Column] = tenant
+	return injected, nil
+}
+
+// Check update Data doesn't move rows to another tenant.
+// Returns error if Data has tenant column which is different from tenant in context.
+func (q *QueryBuilder) checkTenantData(ctx context.Context, table interface{}, data Data) error {
+	tenant, err := q.tenantValue(ctx, table)
+	if err != nil || tenant == nil {
+		return err
+	}
+	if v, ok := data[q.tenant.Column]; ok && !reflect.DeepEqual(v, tenant) {
+		return fmt.Errorf("data has different tenant from context: %v", v)
+	}
+	return nil
+}
+
+// Get source builder of InsertFrom() which is scoped by tenant policy.
+// Inserted rows take tenant value from source rows, so columns must contain tenant column unless table is global table.
+func (q *QueryBuilder) tenantSource(ctx context.Context, table interface{}, columns []string, src *QueryBuilder) (*QueryBuilder, error) {
+	if q.tenant == nil {
+		return src, nil
+	}
+	tenant, err := q.tenantValue(ctx, table)
+	if err != nil {
+		return nil, err
+	}
+	found := false
+	for _, c := range columns {
+		if c == q.tenant.Column {
+			found = true
+		}
+	}
+	if tenant != nil && !found {
+		return nil, fmt.Errorf("columns must contain tenant column %s", q.tenant.Column)
+	}
+	if src.tenant == nil {
+		src = src.Clone()
+		src.tenant = q.tenant
+	}
+	return src, nil
+}
+
+// Get columns and rows of BulkLoad() which are scoped by tenant policy.
+// Tenant value is appended to each row if columns don't contain tenant column, otherwise each row must have the tenant value.
+func (q *QueryBuilder) tenantRows(ctx context.Context, table interface{}, columns []string, rows RowSource) ([]string, RowSource, error) {
+	tenant, err := q.tenantValue(ctx, table)
+	if err != nil || tenant == nil {
+		return columns, rows, err
+	}
+	for i, c := range columns {
+		if c == q.tenant.Column {
+			return columns, &tenantRowSource{rows: rows, tenant: tenant, index: i}, nil
+		}
+	}
+	injected := append(append([]string{}, columns...), q.tenant.Column)
+	return injected, &tenantRowSource{rows: rows, tenant: tenant, index: -1}, nil
+}
+
+// tenantRowSource is RowSource which appends or checks tenant value of each row.
+// index is position of tenant column, or -1 to append tenant value.
+type tenantRowSource struct {
+	rows   RowSource
+	tenant interface{}
+	index  int
+}
+
+// RowSource interface implementation
+func (r *tenantRowSource) Next() ([]interface{}, error) {
+	values, err := r.rows.Next()
+	if err != nil {
+		return nil, err
+	}
+	if r.index < 0 {
+		return append(append([]interface{}{}, values...), r.tenant), nil
+	}
+	if r.index < len(values) && !reflect.DeepEqual(values[r.index], r.tenant) {
+		return nil, fmt.Errorf("row has different tenant from context: %v", values[r.index])
+	}
+	return values, nil
+}
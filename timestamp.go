@@ -0,0 +1,102 @@
+package gqb
+
+import (
+	"time"
+)
+
+// Timestamp is configuration for automatic timestamps.
+// Empty column name is treated as default name of "created_at" or "updated_at".
+// Precision truncates stamped time, and sub-second precision keeps fractional seconds in datetime string.
+type Timestamp struct {
+	CreatedAt string
+	UpdatedAt string
+	Precision time.Duration
+}
+
+// Timestamps option enables automatic timestamps on INSERT and UPDATE query.
+// INSERT query stamps both of CreatedAt and UpdatedAt column, and UPDATE query stamps UpdatedAt column.
+// Columns which already have non-nil value in Data are not overwritten.
+func Timestamps(t Timestamp) Option {
+	if t.CreatedAt == "" {
+		t.CreatedAt = "created_at"
+	}
+	if t.UpdatedAt == "" {
+		t.UpdatedAt = "updated_at"
+	}
+	return func(q *QueryBuilder) {
+		q.timestamp = &t
+	}
+}
+
+// Clock option replaces clock source which is used for timestamps and soft delete.
+// This is useful for deterministic tests.
+func Clock(clock func() time.Time) Option {
+	return func(q *QueryBuilder) {
+		q.clock = clock
+	}
+}
+
+// Get current time from clock source
+func (q *QueryBuilder) now() time.Time {
+	if q.clock != nil {
+		return q.clock()
+	}
+	return time.Now()
+}
+
+// Get current timestamp value with considering precision
+func (q *QueryBuilder) timestampValue() interface{} {
+	now := q.now()
+	if q.timestamp.Precision <= 0 || q.timestamp.Precision >= time.Second {
+		if q.timestamp.Precision > 0 {
+			now = now.Truncate(q.timestamp.Precision)
+		}
+		return now
+	}
+	now = now.Truncate(q.timestamp.Precision)
+	format := datetimeFormat + "."
+	for p := q.timestamp.Precision; p < time.Second; p *= 10 {
+		format += "0"
+	}
+	return q.compat.FormatTime(now, format)
+}
+
+// Create copied Data which stamped timestamps.
+// If created is true, stamps both of created and updated column, otherwise stamps updated column only.
+// Zero time is treated as unset, because it comes from struct field which isn't set.
+func (q *QueryBuilder) stampData(data Data, created bool) Data {
+	if q.timestamp == nil {
+		return data
+	}
+	stamped := Data{}
+	for k, v := range data {
+		stamped[k] = v
+	}
+	now := q.timestampValue()
+	if created && unsetTime(stamped[q.timestamp.CreatedAt]) {
+		stamped[q.timestamp.CreatedAt] = now
+	} else if !created && zeroTime(stamped[q.timestamp.CreatedAt]) {
+		// Zero time comes from struct field which isn't set, so created timestamp isn't overwritten
+		delete(stamped, q.timestamp.CreatedAt)
+	}
+	if unsetTime(stamped[q.timestamp.UpdatedAt]) {
+		stamped[q.timestamp.UpdatedAt] = now
+	}
+	return stamped
+}
+
+// Check timestamp value is unset, it is nil or zero time of struct field
+func unsetTime(v interface{}) bool {
+	return v == nil || zeroTime(v)
+}
+
+// Check value is zero time.Time or pointer to it
+func zeroTime(v interface{}) bool {
+	switch t := v.(type) {
+	case time.Time:
+		return t.IsZero()
+	case *time.Time:
+		return t == nil || t.IsZero()
+	}
+	return false
+}
@@ -2,6 +2,8 @@ package gqb
 
 import (
 	"context"
+	"fmt"
+	"reflect"
 	"sort"
 	"time"
 
@@ -17,10 +19,14 @@ type (
 	// This is suger syntax for map[string]interface{}, but always fields are sorted by key.
 	Data map[string]interface{}
 
+	// Params type is used for named parameters in raw query phrase.
+	// The key corresponds to ":name" or "@name" placeholder.
+	Params map[string]interface{}
+
 	// alias type is used for SELECT, create alias column name.
 	// This will be useful for using JOIN query.
 	alias struct {
-		from string
+		from interface{}
 		to   string
 	}
 
@@ -102,6 +108,18 @@ const (
 	Or CombineType = "OR"
 )
 
+// Option is function which configures QueryBuilder on New()
+type Option func(*QueryBuilder)
+
+type (
+	// Scope is reusable query fragment which is applied by QueryBuilder.Scopes().
+	// Scope can add any of conditions, joins, orders and so on.
+	Scope func(*QueryBuilder) *QueryBuilder
+
+	// GroupScope is reusable condition fragment which is applied by WhereGroup.Scopes().
+	GroupScope func(*WhereGroup) *WhereGroup
+)
+
 // conditionBuilder is private interface with create WHERE condition string.
 type ConditionBuilder interface {
 	// buildCondition() builds WHERE condition string and append bind parameters.
@@ -111,6 +129,12 @@ type ConditionBuilder interface {
 	Combine() string
 }
 
+// validator is private interface for builder parts which may have error on creation.
+// The error is returned on query execution.
+type validator interface {
+	validate() error
+}
+
 // SQL executor interface, this is enough to implement QueryContext() and ExecContext().
 // It's useful for running query in transation or not, because Executor accepts both of *sql.DB and *sql.Tx.
 type Executor interface {
@@ -118,16 +142,31 @@ type Executor interface {
 	ExecContext(context.Context, string, ...interface{}) (sql.Result, error)
 }
 
-func Alias(from, to string) alias {
+// Create table alias, from is table name as string or Ident
+func Alias(from interface{}, to string) alias {
 	return alias{
 		from: from,
 		to:   to,
 	}
 }
 
+// Check aliased table is string or Ident
+func (a alias) validate() error {
+	switch a.from.(type) {
+	case string, Ident:
+		return nil
+	}
+	return fmt.Errorf("aliased table must be string or Ident, but %T is given", a.from)
+}
+
 // fmt.Stringer intetface implementation
 func (a alias) String() string {
-	return quote(a.from) + " AS " + quote(a.to)
+	return a.build(defaultDialect())
+}
+
+// Create table alias phrase on the dialect
+func (a alias) build(compat Compat) string {
+	return aliasTable(compat, quote(compat, schemaTable(a.from)), quote(compat, aliasIdent(a.to)))
 }
 
 // fmt.Stringer intetface implementation
@@ -135,6 +174,12 @@ func (r Raw) String() string {
 	return string(r)
 }
 
+// Create raw phrase which carries bind parameters.
+// Placeholders of "?" or "$n", or ":name" or "@name" with Params are rewritten to fit driver's placeholder and running bind index.
+func (r Raw) Bind(binds ...interface{}) RawExpr {
+	return newRawExpr(string(r), binds)
+}
+
 // Return sorted field name strings
 func (d Data) Keys() []string {
 	keys := make([]string, len(d))
@@ -147,14 +192,97 @@ func (d Data) Keys() []string {
 	return keys
 }
 
+// Create Data from struct fields which have "db" tag.
+// This is useful for struct-driven INSERT/UPDATE, nil pointer field is treated as NULL.
+func NewData(src interface{}) (Data, error) {
+	if src == nil {
+		return nil, fmt.Errorf("source value must be non-nil")
+	}
+	v := derefValue(reflect.ValueOf(src))
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("source value must be a struct: %s", v.Kind())
+	}
+	data := Data{}
+	rt := v.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		// Unexported field can't be read even if it has tag
+		if f.PkgPath != "" {
+			continue
+		}
+		tag, err := parseTag(string(f.Tag))
+		if err != nil {
+			return nil, err
+		}
+		name, ok := tag["db"]
+		if !ok || name == "" || name == "-" {
+			continue
+		}
+		fv := v.Field(i)
+		if fv.Kind() == reflect.Ptr {
+			if fv.IsNil() {
+				data[name] = nil
+				continue
+			}
+			fv = fv.Elem()
+		}
+		data[name] = fv.Interface()
+	}
+	return data, nil
+}
+
 // Order is struct for making ORDER BY phrase
 type Order struct {
 	sort  SortMode
-	field string
+	field interface{}
+}
+
+// Create Order struct, this is useful for specifying sort keys of Cursor
+func NewOrder(field interface{}, sort SortMode) Order {
+	return Order{
+		field: field,
+		sort:  sort,
+	}
+}
+
+// Check field can be quoted, random order doesn't use field
+func (o Order) validate() error {
+	if o.sort == Rand {
+		return nil
+	}
+	return checkIdentType(o.field)
+}
+
+// Return Order which has opposite direction
+func (o Order) reverse() Order {
+	switch o.sort {
+	case Desc:
+		o.sort = Asc
+	case Asc:
+		o.sort = Desc
+	}
+	return o
 }
 
 // Join is struct for making JOIN phrase
 type Join struct {
 	on    condition
-	table string
+	table interface{}
+	// full indicates FULL OUTER JOIN
+	full bool
+}
+
+// Check joined table is string, Ident or alias, and fields of condition can be quoted
+func (j Join) validate() error {
+	if t, ok := j.table.(alias); ok {
+		if err := t.validate(); err != nil {
+			return err
+		}
+	} else if err := checkIdentType(j.table); err != nil {
+		return err
+	}
+	if err := checkIdentType(j.on.field); err != nil {
+		return err
+	}
+	return checkIdentType(j.on.value)
 }
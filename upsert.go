@@ -0,0 +1,125 @@
+package gqb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"database/sql"
+)
+
+// Execute INSERT query which updates existing row on conflict
+func (q *QueryBuilder) Upsert(table interface{}, data Data, conflict ...string) (sql.Result, error) {
+	return q.UpsertContext(context.Background(), table, data, conflict...)
+}
+
+// Execute INSERT query which updates existing row on conflict with context.
+// conflict is columns of unique constraint, columns except conflict and created timestamp are updated.
+//
+// MySQL, MariaDB      -> INSERT ... ON DUPLICATE KEY UPDATE col = VALUES(col)
+// PostgreSQL, SQLite  -> INSERT ... ON CONFLICT (conflict) DO UPDATE SET col = EXCLUDED.col
+// CockroachDB         -> UPSERT INTO ... if conflict is not specified, otherwise the same as PostgreSQL
+//
+// conflict is required on CockroachDB with timestamps or tenant policy, because UPSERT overwrites all columns.
+// Under tenant policy, conflicted row is updated only if it belongs to the tenant,
+// and MySQL and MariaDB refuse upsert because ON DUPLICATE KEY UPDATE can't be restricted.
+func (q *QueryBuilder) UpsertContext(ctx context.Context, table interface{}, data Data, conflict ...string) (sql.Result, error) {
+	q = q.derive()
+	if !q.compat.Capabilities().Supports(FeatureUpsert) {
+		return nil, fmt.Errorf("upsert is not supported on this driver")
+	} else if data == nil {
+		return nil, fmt.Errorf("upsert data must be non-nil")
+	} else if len(data) == 0 {
+		return nil, fmt.Errorf("upsert data must not be empty")
+	} else if err := q.checkIdentifiers(conflict...); err != nil {
+		return nil, err
+	}
+	data = q.stampData(data, true)
+	data, err := q.tenantData(ctx, table, data)
+	if err != nil {
+		return nil, err
+	}
+	tenant, err := q.tenantValue(ctx, table)
+	if err != nil {
+		return nil, err
+	}
+
+	excluded := map[string]bool{}
+	for _, c := range conflict {
+		excluded[c] = true
+	}
+	if q.timestamp != nil {
+		excluded[q.timestamp.CreatedAt] = true
+	}
+	if q.tenant != nil {
+		excluded[q.tenant.Column] = true
+	}
+	columns := []string{}
+	for _, k := range data.Keys() {
+		if !excluded[k] {
+			columns = append(columns, k)
+		}
+	}
+
+	var suffix string
+	style := q.compat.Capabilities().Upsert
+	switch {
+	case style == UpsertOnDuplicateKey && tenant != nil:
+		// ON DUPLICATE KEY UPDATE can't be restricted, so row of another tenant would be overwritten
+		return nil, fmt.Errorf("upsert is not supported under tenant policy on this driver")
+	case style == UpsertOnDuplicateKey:
+		suffix = buildDuplicateKeyUpdate(q.compat, data.Keys(), columns)
+	case len(conflict) > 0:
+		suffix = buildOnConflict(q.compat, conflict, columns)
+		// Update row only if conflicted row belongs to the same tenant
+		if tenant != nil && len(columns) > 0 {
+			column := quote(q.compat, q.tenant.Column)
+			suffix += fmt.Sprintf(" WHERE %s.%s = EXCLUDED.%s", quote(q.compat, targetTable(table)), column, column)
+		}
+	case style != UpsertStatement:
+		return nil, fmt.Errorf("conflict columns must be specified")
+	case q.timestamp != nil || q.tenant != nil:
+		// UPSERT statement replaces all columns, so created timestamp and tenant column would be overwritten
+		return nil, fmt.Errorf("conflict columns must be specified with timestamps or tenant policy")
+	}
+
+	query, binds, err := q.buildInsertQuery(ctx, table, data, "", suffix)
+	if err != nil {
+		return nil, err
+	}
+	// UPSERT statement replaces row by primary key
+	if style == UpsertStatement && len(conflict) == 0 {
+		query = "UPSERT" + strings.TrimPrefix(query, "INSERT")
+	}
+	defer q.Reset()
+	return q.db.ExecContext(ctx, query, binds...)
+}
+
+// Create "ON DUPLICATE KEY UPDATE" phrase.
+// MySQL requires at least one assignment, so the first key is assigned by itself if there are no columns to update.
+func buildDuplicateKeyUpdate(compat Compat, keys, columns []string) string {
+	if len(columns) == 0 {
+		return " ON DUPLICATE KEY UPDATE " + quote(compat, keys[0]) + " = " + quote(compat, keys[0])
+	}
+	updates := []string{}
+	for _, c := range columns {
+		updates = append(updates, quote(compat, c)+" = VALUES("+quote(compat, c)+")")
+	}
+	return " ON DUPLICATE KEY UPDATE " + strings.Join(updates, ", ")
+}
+
+// Create "ON CONFLICT" phrase, it does nothing if there are no columns to update
+func buildOnConflict(compat Compat, conflict, columns []string) string {
+	fields := []string{}
+	for _, c := range conflict {
+		fields = append(fields, quote(compat, c))
+	}
+	if len(columns) == 0 {
+		return " ON CONFLICT (" + strings.Join(fields, ", ") + ") DO NOTHING"
+	}
+	updates := []string{}
+	for _, c := range columns {
+		updates = append(updates, quote(compat, c)+" = EXCLUDED."+quote(compat, c))
+	}
+	return " ON CONFLICT (" + strings.Join(fields, ", ") + ") DO UPDATE SET " + strings.Join(updates, ", ")
+}
@@ -5,26 +5,58 @@ import (
 	"reflect"
 )
 
-var driverCompat Compat = MysqlCompat{}
+var defaultCompat Compat = MysqlCompat{}
 
-func SetDriver(driverType string) {
-	switch driverType {
-	case "mysql":
-		driverCompat = MysqlCompat{}
-	case "postgres":
-		driverCompat = PostgresCompat{}
-	case "sqlite":
-		driverCompat = SQLiteCompat{}
+// Set dialect by driver name, name should be builtin one or registered by RegisterDialect().
+// Unknown name returns error and keeps current dialect.
+func SetDriver(driverType string) error {
+	c, err := lookupDialect(driverType)
+	if err != nil {
+		return err
 	}
+	dialectsMu.Lock()
+	defer dialectsMu.Unlock()
+	defaultCompat = c
+	return nil
 }
 
-// shorthand syntax for compat.Compat.Quote
-func quote(str interface{}) string {
-	if raw, ok := str.(Raw); ok {
-		return string(raw)
-	} else {
-		return driverCompat.Quote(str.(string))
+// Get default dialect which is set by SetDriver(), new builder uses it unless dialect is detected or specified by option
+func defaultDialect() Compat {
+	dialectsMu.RLock()
+	defer dialectsMu.RUnlock()
+	return defaultCompat
+}
+
+// sameKeys() returns true if both of sorted keys are the same
+func sameKeys(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// shorthand syntax for compat.Compat.Quote, Raw is kept as it is and each part of Ident is quoted exactly
+func quote(compat Compat, str interface{}) string {
+	switch v := str.(type) {
+	case Raw:
+		return string(v)
+	case Ident:
+		return v.quote(compat)
+	}
+	return compat.Quote(str.(string))
+}
+
+// Create table alias phrase, some drivers like Oracle don't accept AS keyword for table alias
+func aliasTable(compat Compat, table, alias string) string {
+	if compat.Capabilities().TableAliasWithoutAs {
+		return table + " " + alias
 	}
+	return table + " AS " + alias
 }
 
 // parseTag() parses Strcut tag to name-value map
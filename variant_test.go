@@ -0,0 +1,131 @@
+package gqb_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ysugimoto/gqb"
+)
+
+func runMariaDBTest(t *testing.T) {
+	gqb.SetDriver("mariadb")
+
+	t.Run("InsertReturning() returns error before 10.5", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			InsertReturning(context.Background(), "example", gqb.Data{"name": "John Smith"}, "id")
+		assert.Error(t, err)
+		assert.Equal(t, "", m.query)
+	})
+
+	t.Run("InsertReturning() uses RETURNING on 10.5 or later", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m, gqb.Dialect("mariadb10.5")).
+			InsertReturning(context.Background(), "example", gqb.Data{"name": "John Smith"}, "id")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, "INSERT INTO `example` (`name`) VALUES (?) RETURNING `id`", m.query)
+	})
+
+	t.Run("Delete query with ORDER BY and LIMIT", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			Where("active", 0, gqb.Equal).
+			OrderBy("id", gqb.Asc).
+			Limit(10).
+			Delete("example")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, "DELETE FROM `example` WHERE (`active` = ?) ORDER BY `id` ASC LIMIT 10", m.query)
+	})
+}
+
+func runCockroachTest(t *testing.T) {
+	gqb.SetDriver("cockroachdb")
+
+	t.Run("AsOfSystemTime() reads historical data", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			AsOfSystemTime("-10s").
+			Where("id", 1, gqb.Equal).
+			Get("example")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, `SELECT * FROM "example" AS OF SYSTEM TIME '-10s' WHERE ("id" = $1)`, m.query)
+	})
+
+	t.Run("Upsert() without conflict uses UPSERT statement", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			Upsert("example", gqb.Data{"id": 1, "name": "John Smith"})
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, `UPSERT INTO "example" ("id", "name") VALUES ($1, $2)`, m.query)
+	})
+
+	t.Run("Upsert() without conflict returns error with timestamps", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m, gqb.Timestamps(gqb.Timestamp{})).
+			Upsert("example", gqb.Data{"id": 1, "name": "John Smith"})
+		assert.Error(t, err)
+		assert.Equal(t, "", m.query)
+	})
+
+	t.Run("Upsert() without conflict returns error under tenant policy", func(t *testing.T) {
+		m := &mockExecutor{}
+		ctx := gqb.WithTenant(context.Background(), 10)
+		_, err := gqb.New(m, gqb.Tenant(gqb.TenantPolicy{})).
+			UpsertContext(ctx, "example", gqb.Data{"id": 1, "name": "John Smith"})
+		assert.Error(t, err)
+		assert.Equal(t, "", m.query)
+	})
+
+	t.Run("Delete query with ORDER BY and LIMIT", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			Where("active", 0, gqb.Equal).
+			OrderBy("id", gqb.Asc).
+			Limit(10).
+			Delete("example")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, `DELETE FROM "example" WHERE ("active" = $1) ORDER BY "id" ASC LIMIT 10`, m.query)
+	})
+
+	t.Run("BulkUpdate query uses FROM VALUES list", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			BulkUpdate("example", "id", []gqb.Data{
+				gqb.Data{"id": 1, "name": "John Smith"},
+			})
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, `UPDATE "example" SET "name" = "v"."gqb_1" FROM (SELECT "id", "name" FROM "example" WHERE false UNION ALL VALUES ($1, $2)) AS "v" ("gqb_0", "gqb_1") WHERE ("example"."id" = "v"."gqb_0")`, m.query)
+	})
+}
+
+func runYugabyteTest(t *testing.T) {
+	gqb.SetDriver("yugabytedb")
+
+	t.Run("AsOfSystemTime() returns error", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			AsOfSystemTime("-10s").
+			Get("example")
+		assert.Error(t, err)
+		assert.Equal(t, "", m.query)
+	})
+
+	t.Run("Delete query with LIMIT returns error", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			Where("active", 0, gqb.Equal).
+			Limit(10).
+			Delete("example")
+		assert.Error(t, err)
+		assert.Equal(t, "", m.query)
+	})
+
+	t.Run("Upsert() uses ON CONFLICT", func(t *testing.T) {
+		m := &mockExecutor{}
+		_, err := gqb.New(m).
+			Upsert("example", gqb.Data{"id": 1, "name": "John Smith"}, "id")
+		assert.IsType(t, mockError{}, err)
+		assert.Equal(t, `INSERT INTO "example" ("id", "name") VALUES ($1, $2) ON CONFLICT ("id") DO UPDATE SET "name" = EXCLUDED."name"`, m.query)
+	})
+}
@@ -11,6 +11,7 @@ import (
 type WhereGroup struct {
 	conditions  []ConditionBuilder
 	combineType CombineType
+	err         error
 }
 
 func newWhereGroup(c CombineType) *WhereGroup {
@@ -27,6 +28,11 @@ func (w *WhereGroup) Combine() string {
 
 // ConditionBuilder::Build() interface implementation
 func (w *WhereGroup) Build(binds []interface{}) (string, []interface{}) {
+	return w.buildCompat(defaultDialect(), binds)
+}
+
+// compatBuilder::buildCompat() interface implementation
+func (w *WhereGroup) buildCompat(compat Compat, binds []interface{}) (string, []interface{}) {
 	first := true
 	where := ""
 
@@ -35,25 +41,44 @@ func (w *WhereGroup) Build(binds []interface{}) (string, []interface{}) {
 		if c != "" {
 			c = " " + c + " "
 		}
+		var phrase string
+		phrase, binds = buildCondition(compat, cd, binds)
+		if phrase == "" {
+			continue
+		}
 		if first {
 			c = ""
 			first = false
 		}
-		var phrase string
-		phrase, binds = cd.Build(binds)
 		where += fmt.Sprintf("%s%s", c, phrase)
 	}
 	return where, binds
 }
 
+// Return the first error of conditions
+func (w *WhereGroup) validate() error {
+	return w.err
+}
+
+// Apply scopes in order
+func (w *WhereGroup) Scopes(scopes ...GroupScope) *WhereGroup {
+	for _, s := range scopes {
+		w = s(w)
+	}
+	return w
+}
+
 // Add condition
 func (w *WhereGroup) AddWhere(c ConditionBuilder) *WhereGroup {
+	if v, ok := c.(validator); ok && w.err == nil {
+		w.err = v.validate()
+	}
 	w.conditions = append(w.conditions, c)
 	return w
 }
 
 // Add condition with AND combination
-func (w *WhereGroup) Where(field string, value interface{}, comparison Comparison) *WhereGroup {
+func (w *WhereGroup) Where(field interface{}, value interface{}, comparison Comparison) *WhereGroup {
 	return w.AddWhere(condition{
 		comparison: comparison,
 		field:      field,
@@ -63,7 +88,7 @@ func (w *WhereGroup) Where(field string, value interface{}, comparison Compariso
 }
 
 // Add condition with OR combination
-func (w *WhereGroup) OrWhere(field string, value interface{}, comparison Comparison) *WhereGroup {
+func (w *WhereGroup) OrWhere(field interface{}, value interface{}, comparison Comparison) *WhereGroup {
 	return w.AddWhere(condition{
 		comparison: comparison,
 		field:      field,
@@ -73,7 +98,7 @@ func (w *WhereGroup) OrWhere(field string, value interface{}, comparison Compari
 }
 
 // Add IN condition with AND combination
-func (w *WhereGroup) WhereIn(field string, values ...interface{}) *WhereGroup {
+func (w *WhereGroup) WhereIn(field interface{}, values ...interface{}) *WhereGroup {
 	return w.AddWhere(condition{
 		comparison: In,
 		field:      field,
@@ -83,7 +108,7 @@ func (w *WhereGroup) WhereIn(field string, values ...interface{}) *WhereGroup {
 }
 
 // Add IN condition with OR combination
-func (w *WhereGroup) OrWhereIn(field string, values ...interface{}) *WhereGroup {
+func (w *WhereGroup) OrWhereIn(field interface{}, values ...interface{}) *WhereGroup {
 	return w.AddWhere(condition{
 		comparison: In,
 		field:      field,
@@ -93,7 +118,7 @@ func (w *WhereGroup) OrWhereIn(field string, values ...interface{}) *WhereGroup
 }
 
 // Add LIKE condition with AND combination
-func (w *WhereGroup) Like(field string, value interface{}) *WhereGroup {
+func (w *WhereGroup) Like(field interface{}, value interface{}) *WhereGroup {
 	return w.AddWhere(condition{
 		comparison: Like,
 		field:      field,
@@ -103,7 +128,7 @@ func (w *WhereGroup) Like(field string, value interface{}) *WhereGroup {
 }
 
 // Add LIKE condition with OR combination
-func (w *WhereGroup) OrLike(field string, value interface{}) *WhereGroup {
+func (w *WhereGroup) OrLike(field interface{}, value interface{}) *WhereGroup {
 	return w.AddWhere(condition{
 		comparison: Like,
 		field:      field,
@@ -112,18 +137,20 @@ func (w *WhereGroup) OrLike(field string, value interface{}) *WhereGroup {
 	})
 }
 
-// Add user specific raw condition with AND combination
-func (w *WhereGroup) WhereRaw(raw string) *WhereGroup {
+// Add user specific raw condition with AND combination.
+// Bind parameters are applied to "?" or "$n" placeholders, or Params is applied to ":name" or "@name" placeholders in raw condition.
+func (w *WhereGroup) WhereRaw(raw string, binds ...interface{}) *WhereGroup {
 	return w.AddWhere(rawCondition{
-		rawClause: raw,
-		combine:   And,
+		expr:    newRawExpr(raw, binds),
+		combine: And,
 	})
 }
 
-// Add user specific raw condition with OR combination
-func (w *WhereGroup) OrWhereRaw(raw string) *WhereGroup {
+// Add user specific raw condition with OR combination.
+// Bind parameters are applied to "?" or "$n" placeholders, or Params is applied to ":name" or "@name" placeholders in raw condition.
+func (w *WhereGroup) OrWhereRaw(raw string, binds ...interface{}) *WhereGroup {
 	return w.AddWhere(rawCondition{
-		rawClause: raw,
-		combine:   Or,
+		expr:    newRawExpr(raw, binds),
+		combine: Or,
 	})
 }